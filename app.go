@@ -16,11 +16,13 @@ type _App struct {
 	pid              int
 	windowServerConn net.Conn
 	scenes           map[proto.ID]Scene
+	scenesM          sync.RWMutex // guards scenes, theme and stylesheet, windows are opened and redrawn from different goroutines
 	eventCatcherFl   bool
 	requestActive    bool
 	forwardReplies   []proto.Msg
 	forwardRepliesM  sync.Mutex
-	keyInputChan     *chan *proto.EventRequest
+	theme            *Theme
+	stylesheet       *_Stylesheet
 	quit             chan int
 }
 
@@ -133,9 +135,11 @@ func (app *_App) incomingMessagesHandler() {
 		case *proto.EventRequest:
 			// It's event and must be send to window handler
 			go func() {
-				lId := typed_request.Id
-				channel := app.scenes[lId].getEventChannel()
-				channel <- typed_request
+				scene := app.scene(typed_request.Id)
+				if scene == nil {
+					return
+				}
+				scene.getEventChannel() <- typed_request
 			}()
 		default:
 			// It's message addressed to sendRequest process but received here instead
@@ -149,17 +153,70 @@ func (app *_App) incomingMessagesHandler() {
 	}
 }
 
-func (app *_App) setInput(channel *chan *proto.EventRequest) {
-	app.keyInputChan = channel
-}
-
 func (app *_App) OpenWindow(window Scene) {
 	window.bindApp(app)
 	lid := window.requestLayerId()
+	app.scenesM.Lock()
 	app.scenes[lid] = window
+	app.scenesM.Unlock()
 	// Initial render
-	app.scenes[lid].buildContent()
-	go app.scenes[lid].eventHandler()
+	window.buildContent()
+	go window.eventHandler()
+}
+
+// scene returns window with layer id or nil
+func (app *_App) scene(id proto.ID) Scene {
+	app.scenesM.RLock()
+	defer app.scenesM.RUnlock()
+	return app.scenes[id]
+}
+
+// removeScene forgets closed window
+func (app *_App) removeScene(id proto.ID) {
+	app.scenesM.Lock()
+	defer app.scenesM.Unlock()
+	delete(app.scenes, id)
+}
+
+// invalidateScenes redraws all app windows
+func (app *_App) invalidateScenes() {
+	app.scenesM.RLock()
+	defer app.scenesM.RUnlock()
+	for _, scene := range app.scenes {
+		scene.invalidate()
+	}
+}
+
+// SetTheme sets theme of all app windows (except ones with own theme) and
+// restyles them
+func (app *_App) SetTheme(theme *Theme) {
+	app.scenesM.Lock()
+	app.theme = theme
+	app.scenesM.Unlock()
+	app.invalidateScenes()
+}
+
+// Theme returns current app theme
+func (app *_App) Theme() *Theme {
+	app.scenesM.RLock()
+	defer app.scenesM.RUnlock()
+	return themeOrDefault(app.theme)
+}
+
+// SetStylesheet sets stylesheet of all app windows (except ones with own
+// stylesheet) and restyles them. Nil stylesheet removes styles.
+func (app *_App) SetStylesheet(sheet *_Stylesheet) {
+	app.scenesM.Lock()
+	app.stylesheet = sheet
+	app.scenesM.Unlock()
+	app.invalidateScenes()
+}
+
+// currentStylesheet returns app stylesheet (can be nil)
+func (app *_App) currentStylesheet() *_Stylesheet {
+	app.scenesM.RLock()
+	defer app.scenesM.RUnlock()
+	return app.stylesheet
 }
 
 func (app *_App) Quit() {
@@ -180,6 +237,7 @@ func App(initialScene ...Scene) *_App {
 		appInstance.quit = make(chan int)
 		appInstance.eventCatcherFl = false
 		appInstance.forwardReplies = make([]proto.Msg, 0)
+		appInstance.theme = LightTheme
 		appInstance.establishConnection()
 	})
 	for _, window := range initialScene {
@@ -0,0 +1,125 @@
+package fwsui
+
+import (
+	proto "github.com/Nekhaevalex/fwsprotocol"
+
+	"github.com/nsf/termbox-go"
+)
+
+// _Span – part of attributed string with its own colors and attributes.
+// Colors that are not set are taken from the Text that renders the string,
+// attributes are added to the Text ones.
+type _Span struct {
+	text         string
+	foreground   proto.Color
+	background   proto.Color
+	fgSet, bgSet bool
+	attr         proto.Attr
+}
+
+func (span *_Span) setAttr(flag termbox.Attribute, b bool) *_Span {
+	if b {
+		span.attr = span.attr | proto.Attr(flag)
+	} else {
+		span.attr = span.attr &^ proto.Attr(flag)
+	}
+	return span
+}
+
+func (span *_Span) Foreground(c proto.Color) *_Span {
+	span.foreground = c
+	span.fgSet = true
+	return span
+}
+
+func (span *_Span) Background(c proto.Color) *_Span {
+	span.background = c
+	span.bgSet = true
+	return span
+}
+
+func (span *_Span) Bold(b bool) *_Span {
+	return span.setAttr(termbox.AttrBold, b)
+}
+
+func (span *_Span) Blink(b bool) *_Span {
+	return span.setAttr(termbox.AttrBlink, b)
+}
+
+func (span *_Span) Hidden(b bool) *_Span {
+	return span.setAttr(termbox.AttrHidden, b)
+}
+
+func (span *_Span) Dim(b bool) *_Span {
+	return span.setAttr(termbox.AttrDim, b)
+}
+
+func (span *_Span) Underline(b bool) *_Span {
+	return span.setAttr(termbox.AttrUnderline, b)
+}
+
+func (span *_Span) Cursive(b bool) *_Span {
+	return span.setAttr(termbox.AttrCursive, b)
+}
+
+func (span *_Span) Reverse(b bool) *_Span {
+	return span.setAttr(termbox.AttrReverse, b)
+}
+
+func Span(s string) *_Span {
+	span := new(_Span)
+	span.text = s
+	return span
+}
+
+// _AttributedString – text built from spans with different styles
+type _AttributedString struct {
+	spans []*_Span
+}
+
+// Append adds spans to the end of string
+func (str *_AttributedString) Append(spans ...*_Span) *_AttributedString {
+	str.spans = append(str.spans, spans...)
+	return str
+}
+
+// String returns plain text of attributed string
+func (str *_AttributedString) String() string {
+	s := ""
+	for _, span := range str.spans {
+		s += span.text
+	}
+	return s
+}
+
+// spanAt returns span containing byte offset of plain text or nil
+func (str *_AttributedString) spanAt(offset int) *_Span {
+	if offset < 0 {
+		return nil
+	}
+	start := 0
+	for _, span := range str.spans {
+		if offset < start+len(span.text) {
+			return span
+		}
+		start += len(span.text)
+	}
+	return nil
+}
+
+// Attributed creates attributed string from spans, e.g.
+//
+//	Attributed(Span("File "), Span("not found").Bold(true).Foreground(Red))
+func Attributed(spans ...*_Span) *_AttributedString {
+	str := new(_AttributedString)
+	str.spans = spans
+	return str
+}
+
+// AttributedText creates Text that renders attributed string. Text colors
+// and attributes are used as defaults for spans.
+func AttributedText(str *_AttributedString) *_Text {
+	text := Text(str.String())
+	text.attributed = str
+	return text
+}
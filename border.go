@@ -0,0 +1,385 @@
+package fwsui
+
+import proto "github.com/Nekhaevalex/fwsprotocol"
+
+// BorderStyle – set of box-drawing characters used to draw border
+type BorderStyle uint8
+
+const (
+	SingleBorder BorderStyle = iota
+	DoubleBorder
+	RoundedBorder
+	HeavyBorder
+	ASCIIBorder
+)
+
+// borderRunes – characters of border style: corners (top left, top right,
+// bottom left, bottom right), horizontal and vertical lines
+type borderRunes struct {
+	topLeft, topRight, bottomLeft, bottomRight rune
+	horizontal, vertical                       rune
+}
+
+var borderStyles = map[BorderStyle]borderRunes{
+	SingleBorder:  {'┌', '┐', '└', '┘', '─', '│'},
+	DoubleBorder:  {'╔', '╗', '╚', '╝', '═', '║'},
+	RoundedBorder: {'╭', '╮', '╰', '╯', '─', '│'},
+	HeavyBorder:   {'┏', '┓', '┗', '┛', '━', '┃'},
+	ASCIIBorder:   {'+', '+', '+', '+', '-', '|'},
+}
+
+// _Border – container that draws frame around its child with optional title
+// in the top line. Child is laid out inside the frame.
+type _Border struct {
+	x, y, width, height int
+	awidth, aheight     int
+	sized               bool // size was set explicitly
+	style               BorderStyle
+	foreground          proto.Color
+	background          proto.Color
+	fgRole, bgRole      ColorRole
+	theme               *Theme
+	title               string
+	titleAlign          Align
+	gravityX, gravityY  Align
+	child               View
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
+}
+
+// getGesture implements View.
+func (*_Border) getGesture() Gesture {
+	return nil
+}
+
+// hasGesture implements View.
+func (*_Border) hasGesture() bool {
+	return false
+}
+
+// getLogicalSize returns explicitly set size or the size of child with frame
+// and padding
+func (border *_Border) getLogicalSize() (int, int) {
+	if border.sized {
+		return border.width, border.height
+	}
+	w, h := border.child.getLogicalSize()
+	padding := border.innerPadding()
+	if w >= 0 {
+		w += 2 + padding.horizontal()
+	}
+	if h >= 0 {
+		h += 2 + padding.vertical()
+	}
+	return w, h
+}
+
+func (border *_Border) getActualSize() (int, int) {
+	return border.awidth, border.aheight
+}
+
+func (border *_Border) getPos() (int, int) {
+	return border.x, border.y
+}
+
+func (border *_Border) setPos(x, y int) {
+	border.x = x
+	border.y = y
+}
+
+// insets implements insetView. Padding of border is placed inside frame, so
+// only margin is left to container.
+func (border *_Border) insets() (edges, edges) {
+	return border.margin, edges{}
+}
+
+// stylePadding implements stylePaddedView. Padding set by stylesheet is
+// placed inside frame too.
+func (border *_Border) stylePadding() (edges, bool) {
+	return edges{}, false
+}
+
+// innerPadding returns padding between frame and child
+func (border *_Border) innerPadding() edges {
+	if padding, ok := border.styleable.stylePadding(); ok {
+		return padding
+	}
+	return border.padding
+}
+
+// measure implements measurer. Border takes child size with frame and
+// padding until size is set explicitly.
+func (border *_Border) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	if border.sized {
+		return logicalRange(border.width), logicalRange(border.height)
+	}
+	padding := border.innerPadding()
+	inner := func(size, inset int) int {
+		if size < 0 {
+			return size
+		}
+		return max(0, size-inset)
+	}
+	insetW, insetH := 2+padding.horizontal(), 2+padding.vertical()
+	width, height := measureView(border.child, inner(proposedWidth, insetW), inner(proposedHeight, insetH))
+	return width.add(insetW), height.add(insetH)
+}
+
+func (border *_Border) render(width, height int) [][]proto.Cell {
+	border.awidth = width
+	border.aheight = height
+	canvas := allocateCanvas(width, height)
+	if width <= 0 || height <= 0 {
+		return canvas
+	}
+
+	// Child inside frame and padding
+	padding := border.innerPadding()
+	innerW := max(0, width-2-padding.horizontal())
+	innerH := max(0, height-2-padding.vertical())
+	box := Box(border.child)
+	box.gravityX = border.gravityX
+	box.gravityY = border.gravityY
+	box.setPos(1+padding.left, 1+padding.top)
+	sub_frame := box.render(innerW, innerH)
+	for x := 0; x < innerW; x++ {
+		for y := 0; y < innerH; y++ {
+			canvas[x+1+padding.left][y+1+padding.top] = sub_frame[x][y]
+		}
+	}
+
+	// Frame
+	runes := borderStyles[border.style]
+	fg, bg := border.styleable.style.colors(border.foreground, border.background, border.theme)
+	attr := border.styleable.style.attributes(0)
+	titleAlign, _ := border.styleable.style.alignment(border.titleAlign, Left)
+	put := func(x, y int, ch rune) {
+		canvas[x][y] = proto.Cell{Ch: ch, Fg: fg, Bg: bg, Attribute: attr}
+	}
+	for x := 1; x < width-1; x++ {
+		put(x, 0, runes.horizontal)
+		put(x, height-1, runes.horizontal)
+	}
+	for y := 1; y < height-1; y++ {
+		put(0, y, runes.vertical)
+		put(width-1, y, runes.vertical)
+	}
+	put(0, 0, runes.topLeft)
+	put(width-1, 0, runes.topRight)
+	put(0, height-1, runes.bottomLeft)
+	put(width-1, height-1, runes.bottomRight)
+
+	// Title is placed in the top line between corners with one space margins
+	if border.title != "" && width > 4 {
+		title := truncateLine(splitGraphemes(" "+border.title+" "), width-2, TruncateTail, false)
+		titleWidth := graphemesWidth(title)
+		var start_x int
+		switch titleAlign {
+		case Left:
+			start_x = 1
+		case Center:
+			start_x = width/2 - titleWidth/2
+		case Right:
+			start_x = width - 1 - titleWidth
+		}
+		x := start_x
+		for _, g := range title {
+			x += putGrapheme(canvas, x, 0, g, fg, bg, attr)
+		}
+	}
+	fixWideCells(canvas)
+	return canvas
+}
+
+func (border *_Border) subviews() []View {
+	return []View{border.child}
+}
+
+func (border *_Border) getChildrenGestures(x, y int) []GestureDescriptor {
+	actors := make([]GestureDescriptor, 0)
+	if !viewEnabled(border.child) {
+		return actors
+	}
+	if border.child.hasGesture() {
+		actors = append(actors, border.child.getGesture().getGestureDescriptor(x+border.x, y+border.y))
+	}
+	if asserted, ok := border.child.(Container); ok {
+		actors = append(actors, asserted.getChildrenGestures(x+border.x, y+border.y)...)
+	}
+	return actors
+}
+
+func (border *_Border) Style(style BorderStyle) *_Border {
+	border.style = style
+	return border
+}
+
+func (border *_Border) Foreground(c proto.Color) *_Border {
+	border.foreground = c
+	border.fgRole = NoRole
+	return border
+}
+
+func (border *_Border) Background(c proto.Color) *_Border {
+	border.background = c
+	border.bgRole = NoRole
+	return border
+}
+
+// ForegroundRole makes frame color follow theme color of role
+func (border *_Border) ForegroundRole(role ColorRole) *_Border {
+	border.fgRole = role
+	border.setTheme(border.theme)
+	return border
+}
+
+// BackgroundRole makes frame background follow theme color of role
+func (border *_Border) BackgroundRole(role ColorRole) *_Border {
+	border.bgRole = role
+	border.setTheme(border.theme)
+	return border
+}
+
+// setTheme implements themedView.
+func (border *_Border) setTheme(theme *Theme) {
+	border.theme = theme
+	if border.fgRole != NoRole {
+		border.foreground = themeOrDefault(theme).Color(border.fgRole)
+	}
+	if border.bgRole != NoRole {
+		border.background = themeOrDefault(theme).Color(border.bgRole)
+	}
+}
+
+// Disabled disables (or enables) child of border
+func (border *_Border) Disabled(b bool) *_Border {
+	border.disabled = b
+	return border
+}
+
+// StyleClass sets stylesheet classes of border
+func (border *_Border) StyleClass(classes ...string) *_Border {
+	border.classes = classes
+	return border
+}
+
+// Padding sets space between frame and child
+func (border *_Border) Padding(sides ...int) *_Border {
+	border.padding = edgesOf(sides...)
+	return border
+}
+
+func (border *_Border) Margin(sides ...int) *_Border {
+	border.margin = edgesOf(sides...)
+	return border
+}
+
+func (border *_Border) Flex(weight int) *_Border {
+	border.setFlex(weight)
+	return border
+}
+
+func (border *_Border) LayoutPriority(priority int) *_Border {
+	border.setPriority(priority)
+	return border
+}
+
+func (border *_Border) MinWidth(width int) *_Border {
+	border.setWidthLimits(max(0, width), -1)
+	return border
+}
+
+func (border *_Border) MaxWidth(width int) *_Border {
+	border.setWidthLimits(-1, max(0, width))
+	return border
+}
+
+func (border *_Border) MinHeight(height int) *_Border {
+	border.setHeightLimits(max(0, height), -1)
+	return border
+}
+
+func (border *_Border) MaxHeight(height int) *_Border {
+	border.setHeightLimits(-1, max(0, height))
+	return border
+}
+
+func (border *_Border) Title(title string) *_Border {
+	border.title = title
+	return border
+}
+
+func (border *_Border) TitleAlign(a Align) *_Border {
+	border.titleAlign = a
+	return border
+}
+
+func (border *_Border) Gravity(x, y Align) *_Border {
+	border.gravityX = x
+	border.gravityY = y
+	return border
+}
+
+func (border *_Border) SetSize(width, height int) *_Border {
+	border.width = width
+	border.height = height
+	border.sized = true
+	return border
+}
+
+// SetView sets view shown in border
+func (border *_Border) SetView(view View) *_Border {
+	border.child = viewOrEmpty(view)
+	border.invalidate()
+	return border
+}
+
+// RemoveView removes view from border
+func (border *_Border) RemoveView(view View) *_Border {
+	if view == border.child {
+		border.SetView(nil)
+	}
+	return border
+}
+
+// ReplaceView puts view in place of old one
+func (border *_Border) ReplaceView(old, view View) *_Border {
+	if old == border.child {
+		border.SetView(view)
+	}
+	return border
+}
+
+// Clear removes view from border
+func (border *_Border) Clear() *_Border {
+	return border.SetView(nil)
+}
+
+// Children returns view of border or empty list if it has no view
+func (border *_Border) Children() []View {
+	return childrenOf(border.child)
+}
+
+// Border creates container that draws frame around child. Until size is set
+// explicitly, border takes child size plus frame (floating sizes of child
+// stay floating).
+func Border(child View) *_Border {
+	border := new(_Border)
+	border.child = viewOrEmpty(child)
+	border.style = SingleBorder
+	border.fgRole = BorderRole
+	border.setTheme(nil)
+	border.kind = "Border"
+	border.titleAlign = Left
+	border.gravityX = Center
+	border.gravityY = Center
+	return border
+}
+
+// GroupBox creates titled frame with child placed at its top left corner
+func GroupBox(title string, child View) *_Border {
+	return Border(child).Title(title).Gravity(Left, Left)
+}
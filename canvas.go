@@ -0,0 +1,398 @@
+package fwsui
+
+import (
+	"math"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+)
+
+// _Painter – drawing context passed to Canvas draw function. Coordinates are
+// relative to the top left corner of canvas, everything outside of it is
+// clipped. Colors with alpha are blended over what is already drawn.
+//
+// Dot* methods draw with braille characters: every cell is divided into 2x4
+// dots, so dot coordinates go from 0 to 2*width and 4*height.
+type _Painter struct {
+	cells         [][]proto.Cell
+	width, height int
+	theme         *Theme
+}
+
+// Theme returns theme of the window canvas is shown in
+func (p *_Painter) Theme() *Theme {
+	return themeOrDefault(p.theme)
+}
+
+// Size returns canvas size in cells
+func (p *_Painter) Size() (int, int) {
+	return p.width, p.height
+}
+
+// DotSize returns canvas size in braille dots
+func (p *_Painter) DotSize() (int, int) {
+	return p.width * 2, p.height * 4
+}
+
+func (p *_Painter) inside(x, y int) bool {
+	return x >= 0 && y >= 0 && x < p.width && y < p.height
+}
+
+// Cell returns already drawn cell
+func (p *_Painter) Cell(x, y int) proto.Cell {
+	if !p.inside(x, y) {
+		return proto.Cell{}
+	}
+	return p.cells[x][y]
+}
+
+// SetCell composes cell over the drawn one
+func (p *_Painter) SetCell(x, y int, cell proto.Cell) {
+	if !p.inside(x, y) {
+		return
+	}
+	p.cells[x][y] = cell.Over(p.cells[x][y])
+}
+
+// Fill fills rectangle background with color
+func (p *_Painter) Fill(x, y, width, height int, c proto.Color) {
+	for i := max(0, x); i < min(p.width, x+width); i++ {
+		for j := max(0, y); j < min(p.height, y+height); j++ {
+			p.cells[i][j].Bg = c.Over(p.cells[i][j].Bg)
+		}
+	}
+}
+
+// Clear fills whole canvas with color removing everything drawn
+func (p *_Painter) Clear(c proto.Color) {
+	for i := 0; i < p.width; i++ {
+		for j := 0; j < p.height; j++ {
+			p.cells[i][j] = proto.Cell{Ch: rune(" "[0]), Fg: c, Bg: c}
+		}
+	}
+}
+
+// Put draws character keeping cell background
+func (p *_Painter) Put(x, y int, ch rune, fg proto.Color) {
+	if !p.inside(x, y) {
+		return
+	}
+	cell := p.cells[x][y]
+	g := grapheme{text: string(ch), ch: ch, width: cellWidth(ch)}
+	putGrapheme(p.cells, x, y, g, fg.Over(cell.Bg), cell.Bg, cell.Attribute)
+}
+
+// Text draws single line of text starting at x, y
+func (p *_Painter) Text(x, y int, s string, fg proto.Color) {
+	for _, g := range splitGraphemes(s) {
+		if x >= p.width {
+			return
+		}
+		if p.inside(x, y) {
+			cell := p.cells[x][y]
+			putGrapheme(p.cells, x, y, g, fg.Over(cell.Bg), cell.Bg, cell.Attribute)
+		}
+		x += g.width
+	}
+}
+
+// bresenham calls plot for every point of line between two points
+func bresenham(x0, y0, x1, y1 int, plot func(x, y int)) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		plot(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// ellipse calls plot for points of ellipse outline
+func ellipse(cx, cy, rx, ry int, plot func(x, y int)) {
+	steps := 8 * max(1, max(rx, ry))
+	for i := 0; i < steps; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(steps)
+		x := cx + int(math.Round(float64(rx)*math.Cos(angle)))
+		y := cy + int(math.Round(float64(ry)*math.Sin(angle)))
+		plot(x, y)
+	}
+}
+
+// Line draws line of characters between two cells
+func (p *_Painter) Line(x0, y0, x1, y1 int, ch rune, fg proto.Color) {
+	bresenham(x0, y0, x1, y1, func(x, y int) {
+		p.Put(x, y, ch, fg)
+	})
+}
+
+// Rect draws rectangle outline with box-drawing characters of given style
+func (p *_Painter) Rect(x, y, width, height int, style BorderStyle, fg proto.Color) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	runes := borderStyles[style]
+	right, bottom := x+width-1, y+height-1
+	for i := x + 1; i < right; i++ {
+		p.Put(i, y, runes.horizontal, fg)
+		p.Put(i, bottom, runes.horizontal, fg)
+	}
+	for j := y + 1; j < bottom; j++ {
+		p.Put(x, j, runes.vertical, fg)
+		p.Put(right, j, runes.vertical, fg)
+	}
+	p.Put(x, y, runes.topLeft, fg)
+	p.Put(right, y, runes.topRight, fg)
+	p.Put(x, bottom, runes.bottomLeft, fg)
+	p.Put(right, bottom, runes.bottomRight, fg)
+}
+
+// Circle draws circle of characters. Cells are about twice as high as wide,
+// so horizontal radius is doubled to keep circle round.
+func (p *_Painter) Circle(cx, cy, r int, ch rune, fg proto.Color) {
+	ellipse(cx, cy, 2*r, r, func(x, y int) {
+		p.Put(x, y, ch, fg)
+	})
+}
+
+// Braille dot bits by dot position inside the cell
+var brailleBits = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+const brailleBase = 0x2800
+
+// Dot sets single braille dot. Dots of the same cell are merged, cell takes
+// color of the last dot.
+func (p *_Painter) Dot(x, y int, fg proto.Color) {
+	if x < 0 || y < 0 {
+		return
+	}
+	cx, cy := x/2, y/4
+	if !p.inside(cx, cy) {
+		return
+	}
+	cell := &p.cells[cx][cy]
+	ch := rune(brailleBase)
+	if cell.Ch >= brailleBase && cell.Ch <= brailleBase+0xFF {
+		ch = cell.Ch
+	}
+	cell.Ch = ch | brailleBits[x%2][y%4]
+	cell.Fg = fg.Over(cell.Bg)
+}
+
+// DotLine draws line of braille dots
+func (p *_Painter) DotLine(x0, y0, x1, y1 int, fg proto.Color) {
+	bresenham(x0, y0, x1, y1, func(x, y int) {
+		p.Dot(x, y, fg)
+	})
+}
+
+// DotRect draws rectangle outline of braille dots
+func (p *_Painter) DotRect(x, y, width, height int, fg proto.Color) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	right, bottom := x+width-1, y+height-1
+	p.DotLine(x, y, right, y, fg)
+	p.DotLine(x, bottom, right, bottom, fg)
+	p.DotLine(x, y, x, bottom, fg)
+	p.DotLine(right, y, right, bottom, fg)
+}
+
+// DotCircle draws circle of braille dots
+func (p *_Painter) DotCircle(cx, cy, r int, fg proto.Color) {
+	ellipse(cx, cy, r, r, func(x, y int) {
+		p.Dot(x, y, fg)
+	})
+}
+
+// Plot draws values as line chart over the whole canvas with braille dots.
+// minValue is drawn at the bottom and maxValue at the top.
+func (p *_Painter) Plot(values []float64, minValue, maxValue float64, fg proto.Color) {
+	dotsW, dotsH := p.DotSize()
+	if len(values) == 0 || dotsW == 0 || dotsH == 0 {
+		return
+	}
+	scale := maxValue - minValue
+	if scale == 0 {
+		scale = 1
+	}
+	point := func(i int) (int, int) {
+		x := 0
+		if len(values) > 1 {
+			x = i * (dotsW - 1) / (len(values) - 1)
+		}
+		y := (dotsH - 1) - int(math.Round((values[i]-minValue)/scale*float64(dotsH-1)))
+		return x, y
+	}
+	prevX, prevY := point(0)
+	p.Dot(prevX, prevY, fg)
+	for i := 1; i < len(values); i++ {
+		x, y := point(i)
+		p.DotLine(prevX, prevY, x, y, fg)
+		prevX, prevY = x, y
+	}
+}
+
+// _Canvas – view drawn by user function on every render
+type _Canvas struct {
+	x, y, width, height int
+	awidth, aheight     int
+	background          proto.Color
+	theme               *Theme
+	draw                func(p *_Painter)
+	gestureFlag         bool
+	gesture             Gesture
+	disableable
+	insetable
+	layoutable
+}
+
+func (canvas *_Canvas) getLogicalSize() (int, int) {
+	return canvas.width, canvas.height
+}
+
+func (canvas *_Canvas) getActualSize() (int, int) {
+	if canvas.width > 0 && canvas.height > 0 {
+		return canvas.getLogicalSize()
+	}
+	return canvas.awidth, canvas.aheight
+}
+
+func (canvas *_Canvas) getPos() (int, int) {
+	return canvas.x, canvas.y
+}
+
+func (canvas *_Canvas) setPos(x, y int) {
+	canvas.x = x
+	canvas.y = y
+}
+
+func (canvas *_Canvas) render(width, height int) [][]proto.Cell {
+	canvas.awidth = width
+	canvas.aheight = height
+	painter := &_Painter{cells: allocateCanvas(width, height), width: width, height: height, theme: canvas.theme}
+	if canvas.background.A > 0 {
+		painter.Clear(canvas.background)
+	}
+	if canvas.draw != nil {
+		canvas.draw(painter)
+	}
+	fixWideCells(painter.cells)
+	return painter.cells
+}
+
+// setTheme implements themedView.
+func (canvas *_Canvas) setTheme(theme *Theme) {
+	canvas.theme = theme
+}
+
+func (canvas *_Canvas) hasGesture() bool {
+	return canvas.gestureFlag
+}
+
+func (canvas *_Canvas) getGesture() Gesture {
+	canvas.gesture.setParentViewSizes(canvas)
+	return canvas.gesture
+}
+
+// Disabled disables (or enables) gesture of canvas
+func (canvas *_Canvas) Disabled(b bool) *_Canvas {
+	canvas.disabled = b
+	return canvas
+}
+
+func (canvas *_Canvas) Gesture(gesture Gesture) *_Canvas {
+	canvas.gestureFlag = true
+	canvas.gesture = gesture
+	return canvas
+}
+
+func (canvas *_Canvas) SetSize(width, height int) *_Canvas {
+	canvas.width = width
+	canvas.height = height
+	return canvas
+}
+
+func (canvas *_Canvas) Background(c proto.Color) *_Canvas {
+	canvas.background = c
+	return canvas
+}
+
+func (canvas *_Canvas) Padding(sides ...int) *_Canvas {
+	canvas.padding = edgesOf(sides...)
+	return canvas
+}
+
+func (canvas *_Canvas) Margin(sides ...int) *_Canvas {
+	canvas.margin = edgesOf(sides...)
+	return canvas
+}
+
+func (canvas *_Canvas) Flex(weight int) *_Canvas {
+	canvas.setFlex(weight)
+	return canvas
+}
+
+func (canvas *_Canvas) LayoutPriority(priority int) *_Canvas {
+	canvas.setPriority(priority)
+	return canvas
+}
+
+func (canvas *_Canvas) MinWidth(width int) *_Canvas {
+	canvas.setWidthLimits(max(0, width), -1)
+	return canvas
+}
+
+func (canvas *_Canvas) MaxWidth(width int) *_Canvas {
+	canvas.setWidthLimits(-1, max(0, width))
+	return canvas
+}
+
+func (canvas *_Canvas) MinHeight(height int) *_Canvas {
+	canvas.setHeightLimits(max(0, height), -1)
+	return canvas
+}
+
+func (canvas *_Canvas) MaxHeight(height int) *_Canvas {
+	canvas.setHeightLimits(-1, max(0, height))
+	return canvas
+}
+
+// backgroundColor implements backgroundView.
+func (canvas *_Canvas) backgroundColor() proto.Color {
+	return canvas.background
+}
+
+// OnDraw replaces draw function
+func (canvas *_Canvas) OnDraw(draw func(p *_Painter)) *_Canvas {
+	canvas.draw = draw
+	return canvas
+}
+
+// Canvas creates view with floating size that is drawn by draw function
+// every time it is rendered
+func Canvas(draw func(p *_Painter)) *_Canvas {
+	canvas := new(_Canvas)
+	canvas.width = -1
+	canvas.height = -1
+	canvas.draw = draw
+	return canvas
+}
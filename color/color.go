@@ -0,0 +1,268 @@
+// Package color provides color helpers for FWSUI: hex and HSL/HSV
+// conversions, blending, contrast calculation and named palettes. All
+// functions work with FWS aRGB colors.
+package color
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+)
+
+// ParseHex parses color in "#rgb", "#rgba", "#rrggbb" or "#rrggbbaa" form
+// ("#" is optional). Colors without alpha are opaque.
+func ParseHex(s string) (proto.Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 || len(hex) == 4 {
+		expanded := make([]byte, 0, 2*len(hex))
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return proto.Color{}, fmt.Errorf("color: invalid hex color %q", s)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return proto.Color{}, fmt.Errorf("color: invalid hex color %q", s)
+	}
+	return proto.Color{
+		R: uint8(value >> 24),
+		G: uint8(value >> 16),
+		B: uint8(value >> 8),
+		A: uint8(value),
+	}, nil
+}
+
+// Hex is like ParseHex but panics if color can't be parsed. It's intended
+// for color literals in code.
+func Hex(s string) proto.Color {
+	c, err := ParseHex(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// ToHex formats color as "#rrggbb" or "#rrggbbaa" if it's not opaque
+func ToHex(c proto.Color) string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// RGB returns opaque color
+func RGB(r, g, b uint8) proto.Color {
+	return proto.Color{A: 255, R: r, G: g, B: b}
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func toByte(v float64) uint8 {
+	return uint8(math.Round(clamp01(v) * 255))
+}
+
+func channels(c proto.Color) (float64, float64, float64) {
+	return float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255
+}
+
+// hue returns hue (0..360) of color with given channels and extremes
+func hue(r, g, b, maxC, delta float64) float64 {
+	if delta == 0 {
+		return 0
+	}
+	var h float64
+	switch maxC {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// fromHue builds color from hue, chroma and lightness match value
+func fromHue(h, chroma, m float64, alpha uint8) proto.Color {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return proto.Color{A: alpha, R: toByte(r + m), G: toByte(g + m), B: toByte(b + m)}
+}
+
+// HSL returns opaque color from hue (0..360), saturation and lightness
+// (0..1)
+func HSL(h, s, l float64) proto.Color {
+	s, l = clamp01(s), clamp01(l)
+	chroma := (1 - math.Abs(2*l-1)) * s
+	return fromHue(h, chroma, l-chroma/2, 255)
+}
+
+// ToHSL returns hue (0..360), saturation and lightness (0..1) of color
+func ToHSL(c proto.Color) (h, s, l float64) {
+	r, g, b := channels(c)
+	maxC, minC := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	delta := maxC - minC
+	l = (maxC + minC) / 2
+	if delta != 0 {
+		s = delta / (1 - math.Abs(2*l-1))
+	}
+	return hue(r, g, b, maxC, delta), s, l
+}
+
+// HSV returns opaque color from hue (0..360), saturation and value (0..1)
+func HSV(h, s, v float64) proto.Color {
+	s, v = clamp01(s), clamp01(v)
+	chroma := v * s
+	return fromHue(h, chroma, v-chroma, 255)
+}
+
+// ToHSV returns hue (0..360), saturation and value (0..1) of color
+func ToHSV(c proto.Color) (h, s, v float64) {
+	r, g, b := channels(c)
+	maxC, minC := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	delta := maxC - minC
+	if maxC != 0 {
+		s = delta / maxC
+	}
+	return hue(r, g, b, maxC, delta), s, maxC
+}
+
+// withHSL replaces color with the one built from modified HSL components
+// keeping alpha
+func withHSL(c proto.Color, modify func(h, s, l float64) (float64, float64, float64)) proto.Color {
+	result := HSL(modify(ToHSL(c)))
+	result.A = c.A
+	return result
+}
+
+// Lighten increases lightness of color by amount (0..1)
+func Lighten(c proto.Color, amount float64) proto.Color {
+	return withHSL(c, func(h, s, l float64) (float64, float64, float64) {
+		return h, s, l + amount
+	})
+}
+
+// Darken decreases lightness of color by amount (0..1)
+func Darken(c proto.Color, amount float64) proto.Color {
+	return withHSL(c, func(h, s, l float64) (float64, float64, float64) {
+		return h, s, l - amount
+	})
+}
+
+// Saturate increases saturation of color by amount (0..1), negative amount
+// desaturates color
+func Saturate(c proto.Color, amount float64) proto.Color {
+	return withHSL(c, func(h, s, l float64) (float64, float64, float64) {
+		return h, s + amount, l
+	})
+}
+
+// Rotate shifts hue of color by degrees
+func Rotate(c proto.Color, degrees float64) proto.Color {
+	return withHSL(c, func(h, s, l float64) (float64, float64, float64) {
+		return h + degrees, s, l
+	})
+}
+
+// Mix linearly interpolates between colors (including alpha): t = 0 gives a,
+// t = 1 gives b
+func Mix(a, b proto.Color, t float64) proto.Color {
+	t = clamp01(t)
+	lerp := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	return proto.Color{A: lerp(a.A, b.A), R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B)}
+}
+
+// WithAlpha returns color with replaced alpha
+func WithAlpha(c proto.Color, alpha uint8) proto.Color {
+	c.A = alpha
+	return c
+}
+
+// Fade multiplies alpha of color by factor (0..1)
+func Fade(c proto.Color, factor float64) proto.Color {
+	c.A = toByte(float64(c.A) / 255 * factor)
+	return c
+}
+
+// Invert returns color with inverted channels keeping alpha
+func Invert(c proto.Color) proto.Color {
+	return proto.Color{A: c.A, R: 255 - c.R, G: 255 - c.G, B: 255 - c.B}
+}
+
+// Grayscale returns gray color with the same luminance
+func Grayscale(c proto.Color) proto.Color {
+	v := uint8(math.Round(0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)))
+	return proto.Color{A: c.A, R: v, G: v, B: v}
+}
+
+// Luminance returns WCAG relative luminance of color (0..1)
+func Luminance(c proto.Color) float64 {
+	linear := func(v float64) float64 {
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	r, g, b := channels(c)
+	return 0.2126*linear(r) + 0.7152*linear(g) + 0.0722*linear(b)
+}
+
+// Contrast returns WCAG contrast ratio of two colors (1..21). Text should
+// have ratio at least 4.5 with its background (3 for large text).
+func Contrast(a, b proto.Color) float64 {
+	la, lb := Luminance(a), Luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// ReadableOn returns the candidate with the highest contrast with background.
+// Without candidates black or white is chosen.
+func ReadableOn(background proto.Color, candidates ...proto.Color) proto.Color {
+	if len(candidates) == 0 {
+		candidates = []proto.Color{RGB(0, 0, 0), RGB(255, 255, 255)}
+	}
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if Contrast(c, background) > Contrast(best, background) {
+			best = c
+		}
+	}
+	return best
+}
@@ -0,0 +1,123 @@
+package color
+
+import (
+	"math"
+	"testing"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+func TestParseHex(t *testing.T) {
+	tests := []struct {
+		s    string
+		want proto.Color
+		err  bool
+	}{
+		{"#ff8000", proto.Color{A: 255, R: 255, G: 128, B: 0}, false},
+		{"ff800080", proto.Color{A: 128, R: 255, G: 128, B: 0}, false},
+		{"#f80", proto.Color{A: 255, R: 255, G: 136, B: 0}, false},
+		{"#f808", proto.Color{A: 136, R: 255, G: 136, B: 0}, false},
+		{"#ff800", proto.Color{}, true},
+		{"#gg8000", proto.Color{}, true},
+	}
+	for _, test := range tests {
+		got, err := ParseHex(test.s)
+		if (err != nil) != test.err || got != test.want {
+			t.Errorf("ParseHex(%q) = %v, %v, want %v, error %v", test.s, got, err, test.want, test.err)
+		}
+	}
+	if hex := ToHex(RGB(255, 128, 0)); hex != "#ff8000" {
+		t.Errorf("ToHex = %q, want #ff8000", hex)
+	}
+	if hex := ToHex(proto.Color{A: 128, R: 1, G: 2, B: 3}); hex != "#01020380" {
+		t.Errorf("ToHex = %q, want #01020380", hex)
+	}
+}
+
+func TestHSL(t *testing.T) {
+	tests := []struct {
+		c       proto.Color
+		h, s, l float64
+	}{
+		{RGB(0, 0, 0), 0, 0, 0},
+		{RGB(255, 255, 255), 0, 0, 1},
+		{RGB(128, 128, 128), 0, 0, 0.5},
+		{RGB(255, 0, 0), 0, 1, 0.5},
+		{RGB(0, 255, 0), 120, 1, 0.5},
+		{RGB(0, 0, 255), 240, 1, 0.5},
+		{RGB(255, 0, 255), 300, 1, 0.5},
+		{RGB(0, 0, 128), 240, 1, 0.25},
+		{RGB(191, 64, 64), 0, 0.5, 0.5},
+	}
+	for _, test := range tests {
+		h, s, l := ToHSL(test.c)
+		if !near(h, test.h) || !near(s, test.s) || !near(l, test.l) {
+			t.Errorf("ToHSL(%v) = %.2f, %.2f, %.2f, want %.2f, %.2f, %.2f", test.c, h, s, l, test.h, test.s, test.l)
+		}
+		if c := HSL(h, s, l); c != test.c {
+			t.Errorf("HSL(ToHSL(%v)) = %v", test.c, c)
+		}
+	}
+	// Hue wraps around, saturation and lightness are clamped
+	if c := HSL(-120, 2, 0.5); c != RGB(0, 0, 255) {
+		t.Errorf("HSL(-120, 2, 0.5) = %v, want blue", c)
+	}
+	if c := Lighten(proto.Color{A: 100, R: 255}, 1); c != (proto.Color{A: 100, R: 255, G: 255, B: 255}) {
+		t.Errorf("Lighten keeps alpha and clamps lightness: %v", c)
+	}
+}
+
+func TestHSV(t *testing.T) {
+	for _, c := range []proto.Color{RGB(0, 0, 0), RGB(255, 128, 0), RGB(30, 60, 90), RGB(200, 200, 200)} {
+		if got := HSV(ToHSV(c)); got != c {
+			t.Errorf("HSV(ToHSV(%v)) = %v", c, got)
+		}
+	}
+}
+
+func TestContrast(t *testing.T) {
+	black, white := RGB(0, 0, 0), RGB(255, 255, 255)
+	tests := []struct {
+		a, b proto.Color
+		want float64
+	}{
+		{black, white, 21},
+		{white, black, 21},
+		{white, white, 1},
+		{RGB(118, 118, 118), white, 4.54},
+		{RGB(255, 0, 0), white, 4},
+	}
+	for _, test := range tests {
+		if got := Contrast(test.a, test.b); !near(got, test.want) {
+			t.Errorf("Contrast(%v, %v) = %.2f, want %.2f", test.a, test.b, got, test.want)
+		}
+	}
+	if c := ReadableOn(RGB(20, 20, 60)); c != white {
+		t.Errorf("ReadableOn(dark) = %v, want white", c)
+	}
+	if c := ReadableOn(RGB(250, 240, 200), white, RGB(128, 128, 128), black); c != black {
+		t.Errorf("ReadableOn(light) = %v, want black", c)
+	}
+}
+
+func TestMix(t *testing.T) {
+	a, b := proto.Color{A: 0, R: 0, G: 100, B: 200}, proto.Color{A: 255, R: 255, G: 100, B: 0}
+	tests := []struct {
+		t    float64
+		want proto.Color
+	}{
+		{0, a},
+		{1, b},
+		{0.5, proto.Color{A: 128, R: 128, G: 100, B: 100}},
+		{2, b},
+	}
+	for _, test := range tests {
+		if got := Mix(a, b, test.t); got != test.want {
+			t.Errorf("Mix(%v) = %v, want %v", test.t, got, test.want)
+		}
+	}
+}
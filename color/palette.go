@@ -0,0 +1,87 @@
+package color
+
+import proto "github.com/Nekhaevalex/fwsprotocol"
+
+// Palette – set of named colors
+type Palette map[string]proto.Color
+
+// Get returns color by name or transparent color if palette doesn't have it
+func (palette Palette) Get(name string) proto.Color {
+	return palette[name]
+}
+
+// Basic – eight colors predefined in fwsui package
+var Basic = Palette{
+	"white":     Hex("#ffffff"),
+	"black":     Hex("#000000"),
+	"red":       Hex("#ff0000"),
+	"grey":      Hex("#7f7f7f"),
+	"lightgrey": Hex("#c0c0c0"),
+	"yellow":    Hex("#ffff00"),
+	"green":     Hex("#00ff00"),
+	"blue":      Hex("#0000ff"),
+}
+
+// Tango – Tango Desktop Project palette
+var Tango = Palette{
+	"butter":      Hex("#edd400"),
+	"orange":      Hex("#f57900"),
+	"chocolate":   Hex("#c17d11"),
+	"chameleon":   Hex("#73d216"),
+	"skyblue":     Hex("#3465a4"),
+	"plum":        Hex("#75507b"),
+	"scarletred":  Hex("#cc0000"),
+	"aluminium":   Hex("#d3d7cf"),
+	"darkgrey":    Hex("#555753"),
+	"black":       Hex("#2e3436"),
+	"lightbutter": Hex("#fce94f"),
+	"lightsky":    Hex("#729fcf"),
+}
+
+// Solarized – Solarized palette by Ethan Schoonover
+var Solarized = Palette{
+	"base03":  Hex("#002b36"),
+	"base02":  Hex("#073642"),
+	"base01":  Hex("#586e75"),
+	"base00":  Hex("#657b83"),
+	"base0":   Hex("#839496"),
+	"base1":   Hex("#93a1a1"),
+	"base2":   Hex("#eee8d5"),
+	"base3":   Hex("#fdf6e3"),
+	"yellow":  Hex("#b58900"),
+	"orange":  Hex("#cb4b16"),
+	"red":     Hex("#dc322f"),
+	"magenta": Hex("#d33682"),
+	"violet":  Hex("#6c71c4"),
+	"blue":    Hex("#268bd2"),
+	"cyan":    Hex("#2aa198"),
+	"green":   Hex("#859900"),
+}
+
+// Nord – Nord palette by Arctic Ice Studio
+var Nord = Palette{
+	"polarnight0": Hex("#2e3440"),
+	"polarnight1": Hex("#3b4252"),
+	"polarnight2": Hex("#434c5e"),
+	"polarnight3": Hex("#4c566a"),
+	"snowstorm0":  Hex("#d8dee9"),
+	"snowstorm1":  Hex("#e5e9f0"),
+	"snowstorm2":  Hex("#eceff4"),
+	"frost0":      Hex("#8fbcbb"),
+	"frost1":      Hex("#88c0d0"),
+	"frost2":      Hex("#81a1c1"),
+	"frost3":      Hex("#5e81ac"),
+	"red":         Hex("#bf616a"),
+	"orange":      Hex("#d08770"),
+	"yellow":      Hex("#ebcb8b"),
+	"green":       Hex("#a3be8c"),
+	"purple":      Hex("#b48ead"),
+}
+
+// Palettes – all named palettes by name
+var Palettes = map[string]Palette{
+	"basic":     Basic,
+	"tango":     Tango,
+	"solarized": Solarized,
+	"nord":      Nord,
+}
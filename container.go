@@ -2,24 +2,105 @@ package fwsui
 
 import proto "github.com/Nekhaevalex/fwsprotocol"
 
+// Container – interface for views that contain other views. Children
+// positions are relative to container position (Box is transparent and uses
+// coordinates of its parent). getChildrenGestures receives absolute position
+// of the origin container position is relative to.
 type Container interface {
 	getChildrenGestures(x, y int) []GestureDescriptor
+	subviews() []View
 }
 
+// viewGroup – implemented by views that are laid out as several children of
+// the stack they are placed in (e.g. rows of ForEach)
+type viewGroup interface {
+	groupViews() []View
+}
+
+// flattenViews returns children with views of groups in place of groups
+func flattenViews(children []View) []View {
+	views := make([]View, 0, len(children))
+	for _, child := range children {
+		if group, ok := child.(viewGroup); ok {
+			views = append(views, flattenViews(group.groupViews())...)
+		} else {
+			views = append(views, child)
+		}
+	}
+	return views
+}
+
+// _EmptyView – content of single view container without view, takes no space
+type _EmptyView struct {
+	x, y int
+}
+
+func (*_EmptyView) getLogicalSize() (int, int) {
+	return 0, 0
+}
+
+func (*_EmptyView) getActualSize() (int, int) {
+	return 0, 0
+}
+
+func (empty *_EmptyView) getPos() (int, int) {
+	return empty.x, empty.y
+}
+
+func (empty *_EmptyView) setPos(x, y int) {
+	empty.x = x
+	empty.y = y
+}
+
+func (*_EmptyView) getGesture() Gesture {
+	return nil
+}
+
+func (*_EmptyView) hasGesture() bool {
+	return false
+}
+
+func (*_EmptyView) render(width, height int) [][]proto.Cell {
+	return allocateCanvas(width, height)
+}
+
+// viewOrEmpty returns view or empty view if view is nil
+func viewOrEmpty(view View) View {
+	if view == nil {
+		return new(_EmptyView)
+	}
+	return view
+}
+
+// childrenOf returns children list of single view container
+func childrenOf(child View) []View {
+	if _, ok := child.(*_EmptyView); ok {
+		return []View{}
+	}
+	return []View{child}
+}
+
+// _Box – transparent container placing its child by gravity. Box takes
+// size of its child until SetSize is called.
 type _Box struct {
 	x, y, width, height int
 	awidth, aheight     int
+	sized               bool // size is set with SetSize
 	gravityX            Align
 	gravityY            Align
 	child               View
+	disableable
+	insetable
+	layoutable
+	windowHost
 }
 
 func Box(child View) *_Box {
 	box := new(_Box)
-	box.child = child
+	box.child = viewOrEmpty(child)
 	box.gravityX = Center
 	box.gravityY = Center
-	box.width, box.height = child.getLogicalSize()
+	box.width, box.height = box.child.getLogicalSize()
 	return box
 }
 
@@ -51,62 +132,92 @@ func (box *_Box) setPos(x, y int) {
 func (box *_Box) render(width, height int) [][]proto.Cell {
 	box.awidth = width
 	box.aheight = height
-	// Allocating canvas
 	canvas := allocateCanvas(width, height)
-	// Check if child view has floating size
-	floating_x, floating_y := viewSizeFloating(box.child)
-	c_size_x, c_size_y := box.child.getLogicalSize()
-	// // Set new box size (from render directive arguments)
-	// box.width = width
-	// box.height = height
-
-	// assign child sizes
-	if floating_x {
-		c_size_x = width
-	}
-
-	if floating_y {
-		c_size_y = height
-	}
-
-	// Calculate shifts from gravity parameters
-	var shift_x int
-	var shift_y int
-	switch box.gravityX {
-	case Left:
-		shift_x = 0
-	case Right:
-		shift_x = width - c_size_x
-	case Center:
-		shift_x = width/2 - c_size_x/2
-	}
-	switch box.gravityY {
-	case Left:
-		shift_y = 0
-	case Right:
-		shift_y = height - c_size_y
-	case Center:
-		shift_y = height/2 - c_size_y/2
-	}
-
-	// Calculate drawing start position
-	start_x := max(0, shift_x)
-	start_y := max(0, shift_y)
-	last_x := min(width, shift_x+c_size_x)
-	lasy_y := min(height, shift_y+c_size_y)
-	box.child.setPos(start_x+box.x, start_y+box.y)
-	child_canvas := box.child.render(c_size_x, c_size_y)
-	for x := start_x; x < last_x; x++ {
-		for y := start_y; y < lasy_y; y++ {
-			canvas[x][y] = child_canvas[abs(x-shift_x)][abs(y-shift_y)]
-		}
-	}
+	// Child takes size it accepts in the box and is placed by gravity
+	c_size_x, c_size_y := frameOf(box.child, width, height)
+	shift_x := alignOffset(box.gravityX, width, c_size_x)
+	shift_y := alignOffset(box.gravityY, height, c_size_y)
+	placeView(canvas, box.child, shift_x, shift_y, c_size_x, c_size_y)
+	// Box is transparent, child position is relative to box parent
+	child_x, child_y := box.child.getPos()
+	box.child.setPos(child_x+box.x, child_y+box.y)
+	fixWideCells(canvas)
 	return canvas
 }
 
+// measure implements measurer. Box takes child size until its size is set,
+// floating box can grow.
+func (box *_Box) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	width, height := measureView(box.child, proposedWidth, proposedHeight)
+	if box.width < 0 {
+		width = flexibleSize(width.min, width.ideal)
+	} else if box.sized {
+		width = fixedSize(box.width)
+	}
+	if box.height < 0 {
+		height = flexibleSize(height.min, height.ideal)
+	} else if box.sized {
+		height = fixedSize(box.height)
+	}
+	return width, height
+}
+
+// SetSize sets size of box, negative sizes make it floating
 func (box *_Box) SetSize(width, height int) *_Box {
-	box.width = -1
-	box.height = -1
+	box.width = width
+	box.height = height
+	box.sized = true
+	return box
+}
+
+// Disabled disables (or enables) child of box
+func (box *_Box) Disabled(b bool) *_Box {
+	box.disabled = b
+	return box
+}
+
+func (box *_Box) Padding(sides ...int) *_Box {
+	box.padding = edgesOf(sides...)
+	return box
+}
+
+func (box *_Box) Margin(sides ...int) *_Box {
+	box.margin = edgesOf(sides...)
+	return box
+}
+
+// Flex sets share of free stack space box gets relative to other growing
+// children (1 by default, 0 means box doesn't grow). Box with flex weight
+// grows along stack even if its size is fixed.
+func (box *_Box) Flex(weight int) *_Box {
+	box.setFlex(weight)
+	return box
+}
+
+// LayoutPriority sets priority of box in stack: children with higher
+// priority get free space first (0 by default)
+func (box *_Box) LayoutPriority(priority int) *_Box {
+	box.setPriority(priority)
+	return box
+}
+
+func (box *_Box) MinWidth(width int) *_Box {
+	box.setWidthLimits(max(0, width), -1)
+	return box
+}
+
+func (box *_Box) MaxWidth(width int) *_Box {
+	box.setWidthLimits(-1, max(0, width))
+	return box
+}
+
+func (box *_Box) MinHeight(height int) *_Box {
+	box.setHeightLimits(max(0, height), -1)
+	return box
+}
+
+func (box *_Box) MaxHeight(height int) *_Box {
+	box.setHeightLimits(-1, max(0, height))
 	return box
 }
 
@@ -116,13 +227,53 @@ func (box *_Box) Gravity(x, y Align) *_Box {
 	return box
 }
 
+// SetView sets view placed in box
+func (box *_Box) SetView(view View) *_Box {
+	box.child = viewOrEmpty(view)
+	box.invalidate()
+	return box
+}
+
+// RemoveView removes view from box
+func (box *_Box) RemoveView(view View) *_Box {
+	if view == box.child {
+		box.SetView(nil)
+	}
+	return box
+}
+
+// ReplaceView puts view in place of old one
+func (box *_Box) ReplaceView(old, view View) *_Box {
+	if old == box.child {
+		box.SetView(view)
+	}
+	return box
+}
+
+// Clear removes view from box
+func (box *_Box) Clear() *_Box {
+	return box.SetView(nil)
+}
+
+// Children returns view of box or empty list if box has no view
+func (box *_Box) Children() []View {
+	return childrenOf(box.child)
+}
+
+func (box *_Box) subviews() []View {
+	return []View{box.child}
+}
+
 func (box *_Box) getChildrenGestures(x, y int) []GestureDescriptor {
 	actors := make([]GestureDescriptor, 0, 1)
+	if !viewEnabled(box.child) {
+		return actors
+	}
 	if box.child.hasGesture() {
-		actors = append(actors, box.child.getGesture().getGestureDescriptor(0, 0))
+		actors = append(actors, box.child.getGesture().getGestureDescriptor(x, y))
 	}
 	if asserted, ok := box.child.(Container); ok {
-		actors = append(actors, asserted.getChildrenGestures(box.x, box.y)...)
+		actors = append(actors, asserted.getChildrenGestures(x, y)...)
 	}
 	return actors
 }
@@ -130,10 +281,15 @@ func (box *_Box) getChildrenGestures(x, y int) []GestureDescriptor {
 type _HStack struct {
 	x, y, width, height int
 	awidth, aheight     int
-	padding             int
+	spacing             int
 	gravityX            Align
 	gravityY            Align
 	children            []View
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
 }
 
 // getGesture implements View.
@@ -164,71 +320,125 @@ func (hstack *_HStack) getPos() (int, int) {
 }
 
 func (hstack *_HStack) render(width, height int) [][]proto.Cell {
+	children := flattenViews(hstack.children)
+	spacing := hstack.style.spacingOr(hstack.spacing)
+	_, gravityY := hstack.style.alignment(hstack.gravityX, hstack.gravityY)
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := hstack.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
 	hstack.awidth = width
 	hstack.aheight = height
-	// get total fixed size over X axis, maximal size over Y axis and fixed size elements amount
-	total_fixed_size_X := 0
-	max_size_Y := 0
-	fixed_size_amount := 0
-	for _, child := range hstack.children {
-		x, y := child.getLogicalSize()
-		if x > 0 {
-			total_fixed_size_X += x
-			fixed_size_amount += 1
-		}
-		max_size_Y = max(max_size_Y, y)
+	// Measure pass: widths children accept
+	items := make([]layoutItem, len(children))
+	for i, child := range children {
+		widthRange, _ := measureView(child, -1, height)
+		items[i] = layoutItemOf(child, widthRange, true)
 	}
-	if height < 0 {
-		height = max_size_Y
-	}
-	// calculating horizontal space per floating object
-	floating_objects_amount := len(hstack.children) - fixed_size_amount
-	free_space_per_obj := 0
-	if floating_objects_amount > 0 {
-		free_space_per_obj = (width - total_fixed_size_X - (len(hstack.children)+1)*hstack.padding) / floating_objects_amount
-	}
-	max_x := min(width, total_fixed_size_X+floating_objects_amount*free_space_per_obj)
+	// Arrange pass: fixed children take their width, the rest is shared
+	sizes := distribute(width-spacingSize(len(children), spacing), items)
 	canvas := allocateCanvas(width, height)
-	x := hstack.padding
-	y := 0
-	for _, child := range hstack.children {
-		w, h := child.getLogicalSize()
-		if w < 0 {
-			w = free_space_per_obj
-		}
-		if h < 0 {
-			h = height
-		}
-		box := Box(child)
-		box.gravityX = hstack.gravityX
-		box.gravityY = hstack.gravityY
-		box.setPos(x, y)
-		sub_frame := box.render(w, h)
-		for ix := x; ix < min(max_x, x+w); ix++ {
-			for iy := y; iy < h; iy++ {
-				canvas[ix][iy] = sub_frame[ix-x][iy-y]
-			}
-		}
-		x += (w + hstack.padding)
+	x := 0
+	for i, child := range children {
+		w := sizes[i]
+		_, heightRange := measureView(child, w, height)
+		h := heightRange.clamp(height)
+		y := alignOffset(gravityY, height, h)
+		placeView(canvas, child, x, y, w, h)
+		x += w + spacing
 	}
+	fixWideCells(canvas)
 	return canvas
 }
 
+// measure implements measurer. Stack is as wide as its children with
+// spacing and as high as the highest child; floating stack can grow.
+func (hstack *_HStack) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	children := flattenViews(hstack.children)
+	spacing := hstack.style.spacingOr(hstack.spacing)
+	width := fixedSize(spacingSize(len(children), spacing))
+	height := fixedSize(0)
+	for _, child := range children {
+		w, h := measureView(child, -1, proposedHeight)
+		width.min += w.min
+		width.ideal += w.ideal
+		height.min = max(height.min, h.min)
+		height.ideal = max(height.ideal, h.ideal)
+	}
+	width = flexibleSize(width.min, width.ideal)
+	height = flexibleSize(height.min, height.ideal)
+	if hstack.width >= 0 {
+		width = fixedSize(hstack.width)
+	}
+	if hstack.height >= 0 {
+		height = fixedSize(hstack.height)
+	}
+	return width, height
+}
+
+func (hstack *_HStack) subviews() []View {
+	return hstack.children
+}
+
 func (hstack *_HStack) getChildrenGestures(x, y int) []GestureDescriptor {
 	actors := make([]GestureDescriptor, 0)
 	for _, child := range hstack.children {
+		if !viewEnabled(child) {
+			continue
+		}
 		if child.hasGesture() {
-			actors = append(actors, child.getGesture().getGestureDescriptor(hstack.x, hstack.y))
+			actors = append(actors, child.getGesture().getGestureDescriptor(x+hstack.x, y+hstack.y))
 		}
 		if asserted, ok := child.(Container); ok {
-			actors = append(actors, asserted.getChildrenGestures(hstack.x, hstack.y)...)
+			actors = append(actors, asserted.getChildrenGestures(x+hstack.x, y+hstack.y)...)
 		}
 	}
 	return actors
 }
 
-func (hstack *_HStack) Padding(padding int) *_HStack {
-	hstack.padding = padding
+// Spacing sets space between children of stack
+func (hstack *_HStack) Spacing(spacing int) *_HStack {
+	hstack.spacing = spacing
+	return hstack
+}
+
+func (hstack *_HStack) Padding(sides ...int) *_HStack {
+	hstack.padding = edgesOf(sides...)
+	return hstack
+}
+
+func (hstack *_HStack) Margin(sides ...int) *_HStack {
+	hstack.margin = edgesOf(sides...)
+	return hstack
+}
+
+func (hstack *_HStack) Flex(weight int) *_HStack {
+	hstack.setFlex(weight)
+	return hstack
+}
+
+func (hstack *_HStack) LayoutPriority(priority int) *_HStack {
+	hstack.setPriority(priority)
+	return hstack
+}
+
+func (hstack *_HStack) MinWidth(width int) *_HStack {
+	hstack.setWidthLimits(max(0, width), -1)
+	return hstack
+}
+
+func (hstack *_HStack) MaxWidth(width int) *_HStack {
+	hstack.setWidthLimits(-1, max(0, width))
+	return hstack
+}
+
+func (hstack *_HStack) MinHeight(height int) *_HStack {
+	hstack.setHeightLimits(max(0, height), -1)
+	return hstack
+}
+
+func (hstack *_HStack) MaxHeight(height int) *_HStack {
+	hstack.setHeightLimits(-1, max(0, height))
 	return hstack
 }
 
@@ -244,19 +454,75 @@ func (hstack *_HStack) Gravity(x, y Align) *_HStack {
 	return hstack
 }
 
+// Disabled disables (or enables) all views of stack: they don't react to
+// input and are drawn with disabled style
+func (hstack *_HStack) Disabled(b bool) *_HStack {
+	hstack.disabled = b
+	return hstack
+}
+
+// StyleClass sets stylesheet classes of stack
+func (hstack *_HStack) StyleClass(classes ...string) *_HStack {
+	hstack.classes = classes
+	return hstack
+}
+
+// AddView adds view after other views of stack
 func (hstack *_HStack) AddView(view View) *_HStack {
 	hstack.children = append(hstack.children, view)
+	hstack.invalidate()
+	return hstack
+}
+
+// InsertView inserts view at index, index out of range adds view to the end
+func (hstack *_HStack) InsertView(index int, view View) *_HStack {
+	hstack.children = insertView(hstack.children, index, view)
+	hstack.invalidate()
+	return hstack
+}
+
+// RemoveView removes view from stack
+func (hstack *_HStack) RemoveView(view View) *_HStack {
+	return hstack.RemoveAt(indexOfView(hstack.children, view))
+}
+
+// RemoveAt removes view at index
+func (hstack *_HStack) RemoveAt(index int) *_HStack {
+	hstack.children = removeViewAt(hstack.children, index)
+	hstack.invalidate()
 	return hstack
 }
 
+// ReplaceView puts view in place of old one
+func (hstack *_HStack) ReplaceView(old, view View) *_HStack {
+	if index := indexOfView(hstack.children, old); index >= 0 {
+		hstack.children[index] = view
+		hstack.invalidate()
+	}
+	return hstack
+}
+
+// Clear removes all views from stack
+func (hstack *_HStack) Clear() *_HStack {
+	hstack.children = nil
+	hstack.invalidate()
+	return hstack
+}
+
+// Children returns views of stack
+func (hstack *_HStack) Children() []View {
+	return append([]View(nil), hstack.children...)
+}
+
 func HStack(children ...View) *_HStack {
 	hstack := new(_HStack)
 	hstack.children = children
+	hstack.kind = "HStack"
 	hstack.x = 0
 	hstack.y = 0
 	hstack.width = -1
 	hstack.height = -1
-	hstack.padding = 0
+	hstack.spacing = 0
 	hstack.gravityX = Center
 	hstack.gravityY = Center
 	return hstack
@@ -265,10 +531,15 @@ func HStack(children ...View) *_HStack {
 type _VStack struct {
 	x, y, width, height int
 	awidth, aheight     int
-	padding             int
+	spacing             int
 	gravityX            Align
 	gravityY            Align
 	children            []View
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
 }
 
 // getGesture implements View.
@@ -299,69 +570,131 @@ func (vstack *_VStack) getPos() (int, int) {
 }
 
 func (vstack *_VStack) render(width, height int) [][]proto.Cell {
+	children := flattenViews(vstack.children)
+	spacing := vstack.style.spacingOr(vstack.spacing)
+	gravityX, _ := vstack.style.alignment(vstack.gravityX, vstack.gravityY)
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := vstack.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
 	vstack.awidth = width
 	vstack.aheight = height
-	// get total fixed size over X axis, maximal size over Y axis and fixed size elements amount
-	total_fixed_size_Y := 0
-	max_size_X := 0
-	fixed_size_amount := 0
-	for _, child := range vstack.children {
-		x, y := child.getLogicalSize()
-		if y > 0 {
-			total_fixed_size_Y += y
-			fixed_size_amount += 1
-		}
-		max_size_X = max(max_size_X, x)
-	}
-	if width < 0 {
-		width = max_size_X
-	}
-	// calculating horizontal space per floating object
-	floating_objects_amount := len(vstack.children) - fixed_size_amount
-	free_space_per_obj := 0
-	if floating_objects_amount > 0 {
-		free_space_per_obj = (height - total_fixed_size_Y - len(vstack.children)*vstack.padding) / floating_objects_amount
+	// Measure pass: children get their width first, heights are measured
+	// for it (wrapped text takes more lines in narrow stack)
+	widths := make([]int, len(children))
+	items := make([]layoutItem, len(children))
+	for i, child := range children {
+		widthRange, _ := measureView(child, width, -1)
+		widths[i] = widthRange.clamp(width)
+		_, heightRange := measureView(child, widths[i], -1)
+		items[i] = layoutItemOf(child, heightRange, false)
 	}
-	max_y := min(height, total_fixed_size_Y+floating_objects_amount*free_space_per_obj)
+	// Arrange pass: fixed children take their height, the rest is shared
+	sizes := distribute(height-spacingSize(len(children), spacing), items)
 	canvas := allocateCanvas(width, height)
-	y := vstack.padding
-	x := 0
-	for _, child := range vstack.children {
-		_, h := child.getLogicalSize()
-		if h < 0 {
-			h = free_space_per_obj
-		}
-		w := width
-		box := Box(child)
-		box.gravityX = vstack.gravityX
-		box.gravityY = vstack.gravityY
-		box.setPos(x, y)
-		sub_frame := box.render(w, h)
-		for ix := x; ix < w; ix++ {
-			for iy := y; iy < min(max_y, y+h); iy++ {
-				canvas[ix][iy] = sub_frame[ix-x][iy-y]
-			}
-		}
-		y += (h + vstack.padding)
+	y := 0
+	for i, child := range children {
+		w, h := widths[i], sizes[i]
+		x := alignOffset(gravityX, width, w)
+		placeView(canvas, child, x, y, w, h)
+		y += h + spacing
 	}
+	fixWideCells(canvas)
 	return canvas
 }
 
+// measure implements measurer. Stack is as high as its children with
+// spacing and as wide as the widest child; floating stack can grow.
+func (vstack *_VStack) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	children := flattenViews(vstack.children)
+	spacing := vstack.style.spacingOr(vstack.spacing)
+	if vstack.width >= 0 {
+		proposedWidth = vstack.width
+	}
+	width := fixedSize(0)
+	height := fixedSize(spacingSize(len(children), spacing))
+	for _, child := range children {
+		w, _ := measureView(child, proposedWidth, -1)
+		_, h := measureView(child, w.clamp(proposedWidth), -1)
+		width.min = max(width.min, w.min)
+		width.ideal = max(width.ideal, w.ideal)
+		height.min += h.min
+		height.ideal += h.ideal
+	}
+	width = flexibleSize(width.min, width.ideal)
+	height = flexibleSize(height.min, height.ideal)
+	if vstack.width >= 0 {
+		width = fixedSize(vstack.width)
+	}
+	if vstack.height >= 0 {
+		height = fixedSize(vstack.height)
+	}
+	return width, height
+}
+
+func (vstack *_VStack) subviews() []View {
+	return vstack.children
+}
+
 func (vstack *_VStack) getChildrenGestures(x, y int) []GestureDescriptor {
 	actors := make([]GestureDescriptor, 0)
 	for _, child := range vstack.children {
+		if !viewEnabled(child) {
+			continue
+		}
 		if child.hasGesture() {
-			actors = append(actors, child.getGesture().getGestureDescriptor(vstack.x, vstack.y))
+			actors = append(actors, child.getGesture().getGestureDescriptor(x+vstack.x, y+vstack.y))
 		}
 		if asserted, ok := child.(Container); ok {
-			actors = append(actors, asserted.getChildrenGestures(vstack.x, vstack.y)...)
+			actors = append(actors, asserted.getChildrenGestures(x+vstack.x, y+vstack.y)...)
 		}
 	}
 	return actors
 }
 
-func (vstack *_VStack) Padding(padding int) *_VStack {
-	vstack.padding = padding
+// Spacing sets space between children of stack
+func (vstack *_VStack) Spacing(spacing int) *_VStack {
+	vstack.spacing = spacing
+	return vstack
+}
+
+func (vstack *_VStack) Padding(sides ...int) *_VStack {
+	vstack.padding = edgesOf(sides...)
+	return vstack
+}
+
+func (vstack *_VStack) Margin(sides ...int) *_VStack {
+	vstack.margin = edgesOf(sides...)
+	return vstack
+}
+
+func (vstack *_VStack) Flex(weight int) *_VStack {
+	vstack.setFlex(weight)
+	return vstack
+}
+
+func (vstack *_VStack) LayoutPriority(priority int) *_VStack {
+	vstack.setPriority(priority)
+	return vstack
+}
+
+func (vstack *_VStack) MinWidth(width int) *_VStack {
+	vstack.setWidthLimits(max(0, width), -1)
+	return vstack
+}
+
+func (vstack *_VStack) MaxWidth(width int) *_VStack {
+	vstack.setWidthLimits(-1, max(0, width))
+	return vstack
+}
+
+func (vstack *_VStack) MinHeight(height int) *_VStack {
+	vstack.setHeightLimits(max(0, height), -1)
+	return vstack
+}
+
+func (vstack *_VStack) MaxHeight(height int) *_VStack {
+	vstack.setHeightLimits(-1, max(0, height))
 	return vstack
 }
 
@@ -377,19 +710,75 @@ func (vstack *_VStack) Gravity(x, y Align) *_VStack {
 	return vstack
 }
 
+// Disabled disables (or enables) all views of stack: they don't react to
+// input and are drawn with disabled style
+func (vstack *_VStack) Disabled(b bool) *_VStack {
+	vstack.disabled = b
+	return vstack
+}
+
+// StyleClass sets stylesheet classes of stack
+func (vstack *_VStack) StyleClass(classes ...string) *_VStack {
+	vstack.classes = classes
+	return vstack
+}
+
+// AddView adds view after other views of stack
 func (vstack *_VStack) AddView(view View) *_VStack {
 	vstack.children = append(vstack.children, view)
+	vstack.invalidate()
+	return vstack
+}
+
+// InsertView inserts view at index, index out of range adds view to the end
+func (vstack *_VStack) InsertView(index int, view View) *_VStack {
+	vstack.children = insertView(vstack.children, index, view)
+	vstack.invalidate()
+	return vstack
+}
+
+// RemoveView removes view from stack
+func (vstack *_VStack) RemoveView(view View) *_VStack {
+	return vstack.RemoveAt(indexOfView(vstack.children, view))
+}
+
+// RemoveAt removes view at index
+func (vstack *_VStack) RemoveAt(index int) *_VStack {
+	vstack.children = removeViewAt(vstack.children, index)
+	vstack.invalidate()
+	return vstack
+}
+
+// ReplaceView puts view in place of old one
+func (vstack *_VStack) ReplaceView(old, view View) *_VStack {
+	if index := indexOfView(vstack.children, old); index >= 0 {
+		vstack.children[index] = view
+		vstack.invalidate()
+	}
+	return vstack
+}
+
+// Clear removes all views from stack
+func (vstack *_VStack) Clear() *_VStack {
+	vstack.children = nil
+	vstack.invalidate()
 	return vstack
 }
 
+// Children returns views of stack
+func (vstack *_VStack) Children() []View {
+	return append([]View(nil), vstack.children...)
+}
+
 func VStack(children ...View) *_VStack {
 	vstack := new(_VStack)
 	vstack.children = children
+	vstack.kind = "VStack"
 	vstack.x = 0
 	vstack.y = 0
 	vstack.width = -1
 	vstack.height = -1
-	vstack.padding = 0
+	vstack.spacing = 0
 	vstack.gravityX = Center
 	vstack.gravityY = Center
 	return vstack
@@ -397,8 +786,14 @@ func VStack(children ...View) *_VStack {
 
 type _ZStack struct {
 	x, y, width, height int
+	awidth, aheight     int
 	gravityX, gravityY  Align
 	children            []View
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
 }
 
 // getGesture implements View.
@@ -416,7 +811,7 @@ func (zstack *_ZStack) getLogicalSize() (int, int) {
 }
 
 func (zstack *_ZStack) getActualSize() (int, int) {
-	return zstack.width, zstack.height
+	return zstack.awidth, zstack.aheight
 }
 
 func (zstack *_ZStack) getPos() (int, int) {
@@ -429,12 +824,20 @@ func (zstack *_ZStack) setPos(x, y int) {
 }
 
 func (zstack *_ZStack) render(width, height int) [][]proto.Cell {
+	children := flattenViews(zstack.children)
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := zstack.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
+	zstack.awidth = width
+	zstack.aheight = height
 	canvas := allocateCanvas(width, height)
-	for _, child := range zstack.children {
+	gravityX, gravityY := zstack.style.alignment(zstack.gravityX, zstack.gravityY)
+	for _, child := range children {
 		boxed := Box(child)
-		boxed.gravityX = zstack.gravityX
-		boxed.gravityY = zstack.gravityY
-		boxed.setPos(zstack.x, zstack.y)
+		boxed.gravityX = gravityX
+		boxed.gravityY = gravityY
+		boxed.setPos(0, 0)
 		layer := boxed.render(width, height)
 		for i := 0; i < width; i++ {
 			for j := 0; j < height; j++ {
@@ -442,25 +845,157 @@ func (zstack *_ZStack) render(width, height int) [][]proto.Cell {
 			}
 		}
 	}
+	fixWideCells(canvas)
 	return canvas
 }
 
+// measure implements measurer. Stack is as big as its biggest child,
+// floating stack can grow.
+func (zstack *_ZStack) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	children := flattenViews(zstack.children)
+	width, height := fixedSize(0), fixedSize(0)
+	for _, child := range children {
+		w, h := measureView(child, proposedWidth, proposedHeight)
+		width.min = max(width.min, w.min)
+		width.ideal = max(width.ideal, w.ideal)
+		height.min = max(height.min, h.min)
+		height.ideal = max(height.ideal, h.ideal)
+	}
+	width = flexibleSize(width.min, width.ideal)
+	height = flexibleSize(height.min, height.ideal)
+	if zstack.width >= 0 {
+		width = fixedSize(zstack.width)
+	}
+	if zstack.height >= 0 {
+		height = fixedSize(zstack.height)
+	}
+	return width, height
+}
+
+func (zstack *_ZStack) subviews() []View {
+	return zstack.children
+}
+
 func (zstack *_ZStack) getChildrenGestures(x, y int) []GestureDescriptor {
 	actors := make([]GestureDescriptor, 0)
 	for _, child := range zstack.children {
+		if !viewEnabled(child) {
+			continue
+		}
 		if child.hasGesture() {
-			actors = append(actors, child.getGesture().getGestureDescriptor(zstack.x, zstack.y))
+			actors = append(actors, child.getGesture().getGestureDescriptor(x+zstack.x, y+zstack.y))
 		}
 		if asserted, ok := child.(Container); ok {
-			actors = append(actors, asserted.getChildrenGestures(zstack.x, zstack.y)...)
+			actors = append(actors, asserted.getChildrenGestures(x+zstack.x, y+zstack.y)...)
 		}
 	}
 	return actors
 }
 
+// Disabled disables (or enables) all views of stack: they don't react to
+// input and are drawn with disabled style
+func (zstack *_ZStack) Disabled(b bool) *_ZStack {
+	zstack.disabled = b
+	return zstack
+}
+
+// StyleClass sets stylesheet classes of stack
+func (zstack *_ZStack) StyleClass(classes ...string) *_ZStack {
+	zstack.classes = classes
+	return zstack
+}
+
+func (zstack *_ZStack) Padding(sides ...int) *_ZStack {
+	zstack.padding = edgesOf(sides...)
+	return zstack
+}
+
+func (zstack *_ZStack) Margin(sides ...int) *_ZStack {
+	zstack.margin = edgesOf(sides...)
+	return zstack
+}
+
+func (zstack *_ZStack) Flex(weight int) *_ZStack {
+	zstack.setFlex(weight)
+	return zstack
+}
+
+func (zstack *_ZStack) LayoutPriority(priority int) *_ZStack {
+	zstack.setPriority(priority)
+	return zstack
+}
+
+func (zstack *_ZStack) MinWidth(width int) *_ZStack {
+	zstack.setWidthLimits(max(0, width), -1)
+	return zstack
+}
+
+func (zstack *_ZStack) MaxWidth(width int) *_ZStack {
+	zstack.setWidthLimits(-1, max(0, width))
+	return zstack
+}
+
+func (zstack *_ZStack) MinHeight(height int) *_ZStack {
+	zstack.setHeightLimits(max(0, height), -1)
+	return zstack
+}
+
+func (zstack *_ZStack) MaxHeight(height int) *_ZStack {
+	zstack.setHeightLimits(-1, max(0, height))
+	return zstack
+}
+
+// AddView adds view after other views of stack
+func (zstack *_ZStack) AddView(view View) *_ZStack {
+	zstack.children = append(zstack.children, view)
+	zstack.invalidate()
+	return zstack
+}
+
+// InsertView inserts view at index, index out of range adds view to the end
+func (zstack *_ZStack) InsertView(index int, view View) *_ZStack {
+	zstack.children = insertView(zstack.children, index, view)
+	zstack.invalidate()
+	return zstack
+}
+
+// RemoveView removes view from stack
+func (zstack *_ZStack) RemoveView(view View) *_ZStack {
+	return zstack.RemoveAt(indexOfView(zstack.children, view))
+}
+
+// RemoveAt removes view at index
+func (zstack *_ZStack) RemoveAt(index int) *_ZStack {
+	zstack.children = removeViewAt(zstack.children, index)
+	zstack.invalidate()
+	return zstack
+}
+
+// ReplaceView puts view in place of old one
+func (zstack *_ZStack) ReplaceView(old, view View) *_ZStack {
+	if index := indexOfView(zstack.children, old); index >= 0 {
+		zstack.children[index] = view
+		zstack.invalidate()
+	}
+	return zstack
+}
+
+// Clear removes all views from stack
+func (zstack *_ZStack) Clear() *_ZStack {
+	zstack.children = nil
+	zstack.invalidate()
+	return zstack
+}
+
+// Children returns views of stack
+func (zstack *_ZStack) Children() []View {
+	return append([]View(nil), zstack.children...)
+}
+
 func ZStack(children ...View) *_ZStack {
 	zstack := new(_ZStack)
 	zstack.children = children
+	zstack.kind = "ZStack"
 	zstack.x = 0
 	zstack.y = 0
 	zstack.width = -1
@@ -0,0 +1,123 @@
+package fwsui
+
+import proto "github.com/Nekhaevalex/fwsprotocol"
+
+// _ForEach – group of views built from items of slice. Stack it is placed in
+// lays out its views as its own children. Views are identified by keys of
+// items, so views of items that stay in the slice are kept with their state
+// when the slice changes.
+type _ForEach[T any, K comparable] struct {
+	x, y  int
+	key   func(item T) K
+	build func(item T) View
+	views []View
+	byKey map[K]View
+	windowHost
+}
+
+func (*_ForEach[T, K]) getLogicalSize() (int, int) {
+	return -1, -1
+}
+
+func (*_ForEach[T, K]) getActualSize() (int, int) {
+	return 0, 0
+}
+
+func (group *_ForEach[T, K]) getPos() (int, int) {
+	return group.x, group.y
+}
+
+func (group *_ForEach[T, K]) setPos(x, y int) {
+	group.x = x
+	group.y = y
+}
+
+func (*_ForEach[T, K]) getGesture() Gesture {
+	return nil
+}
+
+func (*_ForEach[T, K]) hasGesture() bool {
+	return false
+}
+
+// render implements View. Views of group are drawn by the stack it is placed
+// in, group itself draws nothing.
+func (*_ForEach[T, K]) render(width, height int) [][]proto.Cell {
+	return allocateCanvas(width, height)
+}
+
+// groupViews implements viewGroup.
+func (group *_ForEach[T, K]) groupViews() []View {
+	return group.views
+}
+
+func (group *_ForEach[T, K]) subviews() []View {
+	return group.views
+}
+
+// getChildrenGestures returns gestures of views of group. Group is
+// transparent, views positions are relative to the stack it is placed in.
+func (group *_ForEach[T, K]) getChildrenGestures(x, y int) []GestureDescriptor {
+	actors := make([]GestureDescriptor, 0)
+	for _, child := range group.views {
+		if !viewEnabled(child) {
+			continue
+		}
+		if child.hasGesture() {
+			actors = append(actors, child.getGesture().getGestureDescriptor(x, y))
+		}
+		if asserted, ok := child.(Container); ok {
+			actors = append(actors, asserted.getChildrenGestures(x, y)...)
+		}
+	}
+	return actors
+}
+
+// Update shows views for new items: views of keys that stay are reused in new
+// order, views are built for new keys and dropped for removed ones. Window is
+// redrawn after update.
+func (group *_ForEach[T, K]) Update(items []T) *_ForEach[T, K] {
+	views := make([]View, len(items))
+	byKey := make(map[K]View, len(items))
+	for i, item := range items {
+		key := group.key(item)
+		view, kept := group.byKey[key]
+		// Items with repeated key get their own views
+		if _, repeated := byKey[key]; repeated {
+			views[i] = group.build(item)
+			continue
+		}
+		if !kept {
+			view = group.build(item)
+		}
+		byKey[key] = view
+		views[i] = view
+	}
+	group.views = views
+	group.byKey = byKey
+	group.invalidate()
+	return group
+}
+
+// Views returns views of group in order of items
+func (group *_ForEach[T, K]) Views() []View {
+	return append([]View(nil), group.views...)
+}
+
+// ForEach creates group of views built for items of slice to be placed in a
+// stack. Key identifies item between updates (e.g. id of record). Build gets
+// a copy of item, so views editing items are bound through slice of pointers:
+//
+//	var tasks []*Task
+//	rows := ForEach(tasks, func(task *Task) int { return task.ID }, func(task *Task) View {
+//		return TextField(&task.Title, "Title")
+//	})
+//	VStack(Text("Tasks"), rows)
+//	...
+//	rows.Update(tasks)
+func ForEach[T any, K comparable](items []T, key func(item T) K, build func(item T) View) *_ForEach[T, K] {
+	group := new(_ForEach[T, K])
+	group.key = key
+	group.build = build
+	return group.Update(items)
+}
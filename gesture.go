@@ -35,8 +35,7 @@ type _AClickGesture struct {
 }
 
 func (click *_AClickGesture) setParentViewSizes(v View) {
-	click.x, click.y = v.getPos()
-	click.width, click.height = v.getActualSize()
+	click.x, click.y, click.width, click.height = hitArea(v)
 }
 
 func (click *_AClickGesture) getGestureDescriptor(x, y int) GestureDescriptor {
@@ -84,13 +83,17 @@ func (click *_AClickGesture) updating(event *proto.EventRequest) {
 }
 
 func (click *_AClickGesture) onChanged() {
-	click.action_changed(click.inside)
+	if click.action_changed != nil {
+		click.action_changed(click.inside)
+	}
 }
 
 func (click *_AClickGesture) onEnded() {
 	click.current_count++
 	if click.current_count == click.count {
-		click.action_ended(click.inside)
+		if click.action_ended != nil {
+			click.action_ended(click.inside)
+		}
 		click.current_count = 0
 	}
 }
@@ -145,6 +148,28 @@ type Value struct {
 	startLocationX, startLocationY int
 	locationX, locationY           int
 	translationX, translationY     int
+	localX, localY                 int // location relative to gesture area
+}
+
+// StartLocation returns window coordinates where gesture started
+func (value Value) StartLocation() (int, int) {
+	return value.startLocationX, value.startLocationY
+}
+
+// Location returns current window coordinates of gesture
+func (value Value) Location() (int, int) {
+	return value.locationX, value.locationY
+}
+
+// LocalLocation returns current coordinates of gesture relative to the top
+// left corner of the view it is attached to
+func (value Value) LocalLocation() (int, int) {
+	return value.localX, value.localY
+}
+
+// Translation returns distance between start and current locations
+func (value Value) Translation() (int, int) {
+	return value.translationX, value.translationY
 }
 
 type _DragGesture struct {
@@ -169,8 +194,7 @@ func (drag *_DragGesture) getGestureDescriptor(x, y int) GestureDescriptor {
 	return descriptor
 }
 func (drag *_DragGesture) setParentViewSizes(v View) {
-	drag.x, drag.y = v.getPos()
-	drag.width, drag.height = v.getActualSize()
+	drag.x, drag.y, drag.width, drag.height = hitArea(v)
 }
 func (drag *_DragGesture) updating(event *proto.EventRequest) {
 	switch event.Key {
@@ -185,6 +209,8 @@ func (drag *_DragGesture) updating(event *proto.EventRequest) {
 		drag.value.locationY = event.MouseY
 		drag.value.translationX = drag.value.locationX - drag.value.startLocationX
 		drag.value.translationY = drag.value.locationY - drag.value.startLocationY
+		drag.value.localX = drag.value.locationX - drag.descriptor.x
+		drag.value.localY = drag.value.locationY - drag.descriptor.y
 		drag.onChanged()
 	case termbox.MouseRelease:
 		if drag.buttonMatched {
@@ -194,6 +220,8 @@ func (drag *_DragGesture) updating(event *proto.EventRequest) {
 			drag.value.locationY = event.MouseY
 			drag.value.translationX = drag.value.locationX - drag.value.startLocationX
 			drag.value.translationY = drag.value.locationY - drag.value.startLocationY
+			drag.value.localX = drag.value.locationX - drag.descriptor.x
+			drag.value.localY = drag.value.locationY - drag.descriptor.y
 			drag.onEnded()
 		} else {
 			if drag.altGesture != nil {
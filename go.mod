@@ -4,10 +4,7 @@ go 1.20
 
 require (
 	github.com/Nekhaevalex/fwsprotocol v0.0.2
+	github.com/mattn/go-runewidth v0.0.14
 	github.com/nsf/termbox-go v1.1.1
-)
-
-require (
-	github.com/mattn/go-runewidth v0.0.14 // indirect
-	github.com/rivo/uniseg v0.4.4 // indirect
+	github.com/rivo/uniseg v0.4.4
 )
@@ -0,0 +1,477 @@
+package fwsui
+
+import (
+	"sort"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+)
+
+// gridTrackKind – the way grid row or column takes its size
+type gridTrackKind uint8
+
+const (
+	fixedTrack    gridTrackKind = iota // Exact size
+	autoTrack                          // Size of the biggest content
+	fractionTrack                      // Share of space left from other tracks
+)
+
+// GridTrack – definition of grid row or column
+type GridTrack struct {
+	kind gridTrackKind
+	size int
+}
+
+// FixedTrack returns track of exact size
+func FixedTrack(size int) GridTrack {
+	return GridTrack{fixedTrack, max(0, size)}
+}
+
+// AutoTrack returns track sized to fit its cells
+func AutoTrack() GridTrack {
+	return GridTrack{autoTrack, 0}
+}
+
+// FractionTrack returns track that takes share of space left after fixed and
+// auto tracks in proportion to weight
+func FractionTrack(weight int) GridTrack {
+	return GridTrack{fractionTrack, max(1, weight)}
+}
+
+// _GridCell – view placed in grid with its position, span and alignment
+type _GridCell struct {
+	view                View
+	row, column         int
+	rowSpan, columnSpan int
+	gravityX, gravityY  Align
+	gravitySet          bool
+}
+
+// GridCell places view in grid cell at row and column (counted from 0)
+func GridCell(view View, row, column int) *_GridCell {
+	cell := new(_GridCell)
+	cell.view = viewOrEmpty(view)
+	cell.row = max(0, row)
+	cell.column = max(0, column)
+	cell.rowSpan = 1
+	cell.columnSpan = 1
+	return cell
+}
+
+// Span sets amount of rows and columns cell takes
+func (cell *_GridCell) Span(rows, columns int) *_GridCell {
+	cell.rowSpan = max(1, rows)
+	cell.columnSpan = max(1, columns)
+	return cell
+}
+
+// Gravity sets alignment of view in cell overriding gravity of grid
+func (cell *_GridCell) Gravity(x, y Align) *_GridCell {
+	cell.gravityX = x
+	cell.gravityY = y
+	cell.gravitySet = true
+	return cell
+}
+
+// _Grid – container that places views in cells of rows and columns. Rows
+// and columns that are not defined are auto tracks.
+type _Grid struct {
+	x, y, width, height int
+	awidth, aheight     int
+	columns, rows       []GridTrack
+	rowGap, columnGap   int
+	gravityX, gravityY  Align
+	cells               []*_GridCell
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
+}
+
+// getGesture implements View.
+func (*_Grid) getGesture() Gesture {
+	return nil
+}
+
+// hasGesture implements View.
+func (*_Grid) hasGesture() bool {
+	return false
+}
+
+func (grid *_Grid) getLogicalSize() (int, int) {
+	return grid.width, grid.height
+}
+
+func (grid *_Grid) getActualSize() (int, int) {
+	return grid.awidth, grid.aheight
+}
+
+func (grid *_Grid) setPos(x, y int) {
+	grid.x = x
+	grid.y = y
+}
+
+func (grid *_Grid) getPos() (int, int) {
+	return grid.x, grid.y
+}
+
+// trackItems returns layout items of tracks along one axis. Auto tracks take
+// the biggest size of their cells before fraction tracks share the rest.
+// Cells spanning several tracks grow auto tracks they span if they don't fit.
+func trackItems(defs []GridTrack, gap int, cells []*_GridCell, position func(*_GridCell) (int, int), size func(*_GridCell) sizeRange) []layoutItem {
+	count := len(defs)
+	for _, cell := range cells {
+		start, span := position(cell)
+		count = max(count, start+span)
+	}
+	items := make([]layoutItem, count)
+	kinds := make([]gridTrackKind, count)
+	for i := range items {
+		track := AutoTrack()
+		if i < len(defs) {
+			track = defs[i]
+		}
+		kinds[i] = track.kind
+		switch track.kind {
+		case fixedTrack:
+			items[i] = layoutItem{size: fixedSize(track.size)}
+		case autoTrack:
+			items[i] = layoutItem{size: fixedSize(0), weight: 1, priority: 1}
+		case fractionTrack:
+			items[i] = layoutItem{size: flexibleSize(0, 0), weight: track.size}
+		}
+	}
+	spanning := make([]*_GridCell, 0)
+	for _, cell := range cells {
+		start, span := position(cell)
+		if span > 1 {
+			spanning = append(spanning, cell)
+			continue
+		}
+		if kinds[start] == fixedTrack {
+			continue
+		}
+		r := size(cell)
+		item := &items[start]
+		item.size.min = max(item.size.min, r.min)
+		item.size.ideal = max(item.size.ideal, r.ideal)
+		if kinds[start] == autoTrack {
+			item.size.max = item.size.ideal
+		}
+	}
+	for _, cell := range spanning {
+		start, span := position(cell)
+		ideal := (span - 1) * gap
+		auto := make([]int, 0)
+		for i := start; i < start+span; i++ {
+			ideal += items[i].size.ideal
+			if kinds[i] == autoTrack {
+				auto = append(auto, i)
+			}
+		}
+		lack := size(cell).ideal - ideal
+		if lack <= 0 || len(auto) == 0 {
+			continue
+		}
+		for n, i := range auto {
+			share := lack / len(auto)
+			if n < lack%len(auto) {
+				share++
+			}
+			items[i].size.ideal += share
+			items[i].size.max = items[i].size.ideal
+		}
+	}
+	return items
+}
+
+// trackOffsets returns positions of tracks of given sizes separated by gap
+func trackOffsets(sizes []int, gap int) []int {
+	offsets := make([]int, len(sizes))
+	offset := 0
+	for i, size := range sizes {
+		offsets[i] = offset
+		offset += size + gap
+	}
+	return offsets
+}
+
+// spanSize returns size of span of tracks with gaps between them
+func spanSize(sizes []int, gap, start, span int) int {
+	size := (span - 1) * gap
+	for i := start; i < start+span; i++ {
+		size += sizes[i]
+	}
+	return size
+}
+
+func cellColumns(cell *_GridCell) (int, int) {
+	return cell.column, cell.columnSpan
+}
+
+func cellRows(cell *_GridCell) (int, int) {
+	return cell.row, cell.rowSpan
+}
+
+// columnItems measures columns of grid
+func (grid *_Grid) columnItems() []layoutItem {
+	return trackItems(grid.columns, grid.columnGap, grid.cells, cellColumns, func(cell *_GridCell) sizeRange {
+		width, _ := measureView(cell.view, -1, -1)
+		return width
+	})
+}
+
+// rowItems measures rows of grid for widths of columns
+func (grid *_Grid) rowItems(widths []int) []layoutItem {
+	return trackItems(grid.rows, grid.rowGap, grid.cells, cellRows, func(cell *_GridCell) sizeRange {
+		available := spanSize(widths, grid.columnGap, cell.column, cell.columnSpan)
+		width, _ := measureView(cell.view, available, -1)
+		_, height := measureView(cell.view, width.clamp(available), -1)
+		return height
+	})
+}
+
+// trackRange returns range of size of all tracks with gaps. Grid can grow
+// only if it has fraction tracks.
+func trackRange(items []layoutItem, gap int) sizeRange {
+	r := fixedSize(spacingSize(len(items), gap))
+	for _, item := range items {
+		r.min += item.size.min
+		r.ideal += item.size.ideal
+		r.max = min(unbounded, r.max+item.size.max)
+	}
+	return r
+}
+
+func (grid *_Grid) render(width, height int) [][]proto.Cell {
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := grid.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
+	grid.awidth = width
+	grid.aheight = height
+	gravityX, gravityY := grid.style.alignment(grid.gravityX, grid.gravityY)
+	// Columns are arranged first, rows are measured for widths of columns
+	columns := grid.columnItems()
+	widths := distribute(width-spacingSize(len(columns), grid.columnGap), columns)
+	rows := grid.rowItems(widths)
+	heights := distribute(height-spacingSize(len(rows), grid.rowGap), rows)
+	columnOffsets := trackOffsets(widths, grid.columnGap)
+	rowOffsets := trackOffsets(heights, grid.rowGap)
+	canvas := allocateCanvas(width, height)
+	for _, cell := range grid.cells {
+		cellWidth := spanSize(widths, grid.columnGap, cell.column, cell.columnSpan)
+		cellHeight := spanSize(heights, grid.rowGap, cell.row, cell.rowSpan)
+		cellGravityX, cellGravityY := gravityX, gravityY
+		if cell.gravitySet {
+			cellGravityX, cellGravityY = cell.gravityX, cell.gravityY
+		}
+		w, h := frameOf(cell.view, cellWidth, cellHeight)
+		x := columnOffsets[cell.column] + alignOffset(cellGravityX, cellWidth, w)
+		y := rowOffsets[cell.row] + alignOffset(cellGravityY, cellHeight, h)
+		placeView(canvas, cell.view, x, y, w, h)
+	}
+	fixWideCells(canvas)
+	return canvas
+}
+
+// measure implements measurer. Grid is as big as its tracks with gaps;
+// floating grid can grow if it has fraction tracks.
+func (grid *_Grid) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	if grid.width >= 0 {
+		proposedWidth = grid.width
+	}
+	columns := grid.columnItems()
+	width := trackRange(columns, grid.columnGap)
+	widths := distribute(width.clamp(proposedWidth)-spacingSize(len(columns), grid.columnGap), columns)
+	height := trackRange(grid.rowItems(widths), grid.rowGap)
+	if grid.width >= 0 {
+		width = fixedSize(grid.width)
+	}
+	if grid.height >= 0 {
+		height = fixedSize(grid.height)
+	}
+	return width, height
+}
+
+// subviews returns views of grid row by row, so focus moves along rows
+func (grid *_Grid) subviews() []View {
+	views := make([]View, len(grid.cells))
+	for i, cell := range grid.cells {
+		views[i] = cell.view
+	}
+	return views
+}
+
+func (grid *_Grid) getChildrenGestures(x, y int) []GestureDescriptor {
+	actors := make([]GestureDescriptor, 0)
+	for _, cell := range grid.cells {
+		child := cell.view
+		if !viewEnabled(child) {
+			continue
+		}
+		if child.hasGesture() {
+			actors = append(actors, child.getGesture().getGestureDescriptor(x+grid.x, y+grid.y))
+		}
+		if asserted, ok := child.(Container); ok {
+			actors = append(actors, asserted.getChildrenGestures(x+grid.x, y+grid.y)...)
+		}
+	}
+	return actors
+}
+
+// Rows sets definitions of rows
+func (grid *_Grid) Rows(rows ...GridTrack) *_Grid {
+	grid.rows = rows
+	return grid
+}
+
+// Columns sets definitions of columns
+func (grid *_Grid) Columns(columns ...GridTrack) *_Grid {
+	grid.columns = columns
+	return grid
+}
+
+// Gap sets space between rows and between columns
+func (grid *_Grid) Gap(rowGap, columnGap int) *_Grid {
+	grid.rowGap = max(0, rowGap)
+	grid.columnGap = max(0, columnGap)
+	return grid
+}
+
+// AddCell adds cell to grid
+func (grid *_Grid) AddCell(cell *_GridCell) *_Grid {
+	index := sort.Search(len(grid.cells), func(i int) bool {
+		other := grid.cells[i]
+		return other.row > cell.row || (other.row == cell.row && other.column > cell.column)
+	})
+	grid.cells = append(grid.cells, nil)
+	copy(grid.cells[index+1:], grid.cells[index:])
+	grid.cells[index] = cell
+	grid.invalidate()
+	return grid
+}
+
+// AddView adds view to grid cell at row and column
+func (grid *_Grid) AddView(view View, row, column int) *_Grid {
+	return grid.AddCell(GridCell(view, row, column))
+}
+
+// RemoveView removes cell with view from grid
+func (grid *_Grid) RemoveView(view View) *_Grid {
+	return grid.RemoveAt(indexOfView(grid.subviews(), view))
+}
+
+// RemoveAt removes cell at index of grid views (row by row)
+func (grid *_Grid) RemoveAt(index int) *_Grid {
+	if index < 0 || index >= len(grid.cells) {
+		return grid
+	}
+	grid.cells = append(grid.cells[:index], grid.cells[index+1:]...)
+	grid.invalidate()
+	return grid
+}
+
+// ReplaceView puts view in the cell of old one
+func (grid *_Grid) ReplaceView(old, view View) *_Grid {
+	if index := indexOfView(grid.subviews(), old); index >= 0 {
+		grid.cells[index].view = viewOrEmpty(view)
+		grid.invalidate()
+	}
+	return grid
+}
+
+// Clear removes all cells from grid, track definitions are kept
+func (grid *_Grid) Clear() *_Grid {
+	grid.cells = nil
+	grid.invalidate()
+	return grid
+}
+
+// Children returns views of grid row by row
+func (grid *_Grid) Children() []View {
+	return grid.subviews()
+}
+
+func (grid *_Grid) SetSize(x, y int) *_Grid {
+	grid.width = x
+	grid.height = y
+	return grid
+}
+
+// Gravity sets alignment of views in their cells
+func (grid *_Grid) Gravity(x, y Align) *_Grid {
+	grid.gravityX = x
+	grid.gravityY = y
+	return grid
+}
+
+// Disabled disables (or enables) all views of grid: they don't react to
+// input and are drawn with disabled style
+func (grid *_Grid) Disabled(b bool) *_Grid {
+	grid.disabled = b
+	return grid
+}
+
+// StyleClass sets stylesheet classes of grid
+func (grid *_Grid) StyleClass(classes ...string) *_Grid {
+	grid.classes = classes
+	return grid
+}
+
+func (grid *_Grid) Padding(sides ...int) *_Grid {
+	grid.padding = edgesOf(sides...)
+	return grid
+}
+
+func (grid *_Grid) Margin(sides ...int) *_Grid {
+	grid.margin = edgesOf(sides...)
+	return grid
+}
+
+func (grid *_Grid) Flex(weight int) *_Grid {
+	grid.setFlex(weight)
+	return grid
+}
+
+func (grid *_Grid) LayoutPriority(priority int) *_Grid {
+	grid.setPriority(priority)
+	return grid
+}
+
+func (grid *_Grid) MinWidth(width int) *_Grid {
+	grid.setWidthLimits(max(0, width), -1)
+	return grid
+}
+
+func (grid *_Grid) MaxWidth(width int) *_Grid {
+	grid.setWidthLimits(-1, max(0, width))
+	return grid
+}
+
+func (grid *_Grid) MinHeight(height int) *_Grid {
+	grid.setHeightLimits(max(0, height), -1)
+	return grid
+}
+
+func (grid *_Grid) MaxHeight(height int) *_Grid {
+	grid.setHeightLimits(-1, max(0, height))
+	return grid
+}
+
+// Grid creates grid with column definitions, e.g. form with labels aligned
+// to the right and fields taking the rest of width:
+//
+//	Grid(AutoTrack(), FractionTrack(1)).Gap(0, 1).
+//		AddCell(GridCell(Text("Name:"), 0, 0).Gravity(Right, Center)).
+//		AddView(TextField(&name, "Name"), 0, 1)
+func Grid(columns ...GridTrack) *_Grid {
+	grid := new(_Grid)
+	grid.columns = columns
+	grid.kind = "Grid"
+	grid.width = -1
+	grid.height = -1
+	grid.gravityX = Left
+	grid.gravityY = Center
+	return grid
+}
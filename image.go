@@ -0,0 +1,432 @@
+package fwsui
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+)
+
+// ScaleMode – defines how image is scaled to the view area
+type ScaleMode uint8
+
+const (
+	ScaleFit     ScaleMode = iota // Whole image is shown keeping proportions
+	ScaleFill                     // Whole area is covered keeping proportions, image is cropped
+	ScaleStretch                  // Image is stretched to the area
+)
+
+// _Image – view showing raster image with half-block characters: every cell
+// shows two vertical pixels. Animated GIFs are played while image is shown
+// in a window.
+type _Image struct {
+	x, y, width, height int
+	awidth, aheight     int
+	scale               ScaleMode
+	frames              []image.Image
+	delays              []time.Duration
+	loopCount           int // GIF loop count: 0 – forever, -1 – once
+	frame               int
+	playing             bool
+	stop                chan int // closed to stop animation goroutine, nil if it isn't running
+	host                *_Window
+	cache               [][]proto.Cell // last rendered frame
+	cacheFrame          int
+	m                   sync.Mutex
+	gestureFlag         bool
+	gesture             Gesture
+	disableable
+	insetable
+	layoutable
+}
+
+// colorOf converts pixel color to FWS color keeping alpha
+func colorOf(c color.Color) proto.Color {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return proto.Color{A: nrgba.A, R: nrgba.R, G: nrgba.G, B: nrgba.B}
+}
+
+// samplePixel returns average color of source rectangle (box filter) with
+// premultiplied alpha, so transparent pixels don't darken the result
+func samplePixel(img image.Image, x0, y0, x1, y1 float64) proto.Color {
+	bounds := img.Bounds()
+	ix0 := bounds.Min.X + int(x0)
+	iy0 := bounds.Min.Y + int(y0)
+	ix1 := max(ix0+1, bounds.Min.X+int(x1))
+	iy1 := max(iy0+1, bounds.Min.Y+int(y1))
+	var r, g, b, a, n uint64
+	for x := ix0; x < min(ix1, bounds.Max.X); x++ {
+		for y := iy0; y < min(iy1, bounds.Max.Y); y++ {
+			pr, pg, pb, pa := img.At(x, y).RGBA()
+			r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+			n++
+		}
+	}
+	if n == 0 || a == 0 {
+		return proto.Color{}
+	}
+	return colorOf(color.RGBA64{
+		R: uint16(r / n),
+		G: uint16(g / n),
+		B: uint16(b / n),
+		A: uint16(a / n),
+	})
+}
+
+// halfBlock builds cell showing two vertical pixels. More opaque pixel is
+// drawn with character foreground and the other one with background, so
+// transparency is handled by Cell.Over.
+func halfBlock(top, bottom proto.Color) proto.Cell {
+	if top.A == 0 && bottom.A == 0 {
+		return proto.Cell{Ch: rune(" "[0])}
+	}
+	if top.A >= bottom.A {
+		return proto.Cell{Ch: '▀', Fg: top, Bg: bottom}
+	}
+	return proto.Cell{Ch: '▄', Fg: bottom, Bg: top}
+}
+
+// scaleFrame renders image to width x height cells according to scale mode
+func (img *_Image) scaleFrame(frame image.Image, width, height int) [][]proto.Cell {
+	canvas := allocateCanvas(width, height)
+	bounds := frame.Bounds()
+	srcW, srcH := float64(bounds.Dx()), float64(bounds.Dy())
+	dstW, dstH := float64(width), float64(height*2)
+	if srcW == 0 || srcH == 0 || dstW == 0 || dstH == 0 {
+		return canvas
+	}
+	scaleX, scaleY := dstW/srcW, dstH/srcH
+	switch img.scale {
+	case ScaleFit:
+		scaleX = min64(scaleX, scaleY)
+		scaleY = scaleX
+	case ScaleFill:
+		scaleX = max64(scaleX, scaleY)
+		scaleY = scaleX
+	}
+	// Image is centered in the area
+	offsetX := (dstW - srcW*scaleX) / 2
+	offsetY := (dstH - srcH*scaleY) / 2
+	pixel := func(px, py int) proto.Color {
+		sx := (float64(px) - offsetX) / scaleX
+		sy := (float64(py) - offsetY) / scaleY
+		if sx < 0 || sy < 0 || sx >= srcW || sy >= srcH {
+			return proto.Color{}
+		}
+		return samplePixel(frame, sx, sy, sx+1/scaleX, sy+1/scaleY)
+	}
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			canvas[x][y] = halfBlock(pixel(x, 2*y), pixel(x, 2*y+1))
+		}
+	}
+	return canvas
+}
+
+func min64(x, y float64) float64 {
+	if x > y {
+		return y
+	}
+	return x
+}
+
+func max64(x, y float64) float64 {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+func (img *_Image) getLogicalSize() (int, int) {
+	return img.width, img.height
+}
+
+func (img *_Image) getActualSize() (int, int) {
+	if img.width > 0 && img.height > 0 {
+		return img.getLogicalSize()
+	}
+	return img.awidth, img.aheight
+}
+
+func (img *_Image) getPos() (int, int) {
+	return img.x, img.y
+}
+
+func (img *_Image) setPos(x, y int) {
+	img.x = x
+	img.y = y
+}
+
+func (img *_Image) render(width, height int) [][]proto.Cell {
+	img.m.Lock()
+	defer img.m.Unlock()
+	if len(img.frames) == 0 {
+		return allocateCanvas(width, height)
+	}
+	if img.cache == nil || img.cacheFrame != img.frame || img.awidth != width || img.aheight != height {
+		img.cache = img.scaleFrame(img.frames[img.frame], width, height)
+		img.cacheFrame = img.frame
+	}
+	img.awidth = width
+	img.aheight = height
+	canvas := allocateCanvas(width, height)
+	for x := range img.cache {
+		copy(canvas[x], img.cache[x])
+	}
+	return canvas
+}
+
+func (img *_Image) hasGesture() bool {
+	return img.gestureFlag
+}
+
+func (img *_Image) getGesture() Gesture {
+	img.gesture.setParentViewSizes(img)
+	return img.gesture
+}
+
+// setHost implements hostedView. Animation starts when image gets into
+// window and stops when image leaves it (window is nil).
+func (img *_Image) setHost(window *_Window) {
+	img.m.Lock()
+	defer img.m.Unlock()
+	img.host = window
+	switch {
+	case window == nil:
+		img.stopAnimation()
+	case img.playing && img.stop == nil && len(img.frames) > 1:
+		img.stop = make(chan int)
+		go img.animate(img.stop)
+	}
+}
+
+// stopAnimation stops animation goroutine. Must be called with img.m locked.
+func (img *_Image) stopAnimation() {
+	if img.stop != nil {
+		close(img.stop)
+		img.stop = nil
+	}
+}
+
+// animate switches frames according to their delays until animation is
+// stopped, image leaves window or loop count is exhausted
+func (img *_Image) animate(stop chan int) {
+	img.m.Lock()
+	ticker := time.NewTicker(img.delays[img.frame])
+	img.m.Unlock()
+	defer ticker.Stop()
+	loops := 0
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+		img.m.Lock()
+		if img.host == nil || img.stop != stop {
+			img.m.Unlock()
+			return
+		}
+		img.frame = (img.frame + 1) % len(img.frames)
+		if img.frame == 0 {
+			loops++
+			if img.loopCount == -1 || (img.loopCount > 0 && loops > img.loopCount) {
+				img.frame = len(img.frames) - 1
+				img.playing = false
+				img.stopAnimation()
+				img.m.Unlock()
+				return
+			}
+		}
+		ticker.Reset(img.delays[img.frame])
+		host := img.host
+		img.m.Unlock()
+		host.invalidate()
+	}
+}
+
+// Play starts GIF animation (animation plays by default)
+func (img *_Image) Play() *_Image {
+	img.m.Lock()
+	img.playing = true
+	host := img.host
+	img.m.Unlock()
+	if host != nil {
+		img.setHost(host)
+	}
+	return img
+}
+
+// Pause stops GIF animation at current frame
+func (img *_Image) Pause() *_Image {
+	img.m.Lock()
+	img.playing = false
+	img.stopAnimation()
+	img.m.Unlock()
+	return img
+}
+
+func (img *_Image) Scale(mode ScaleMode) *_Image {
+	img.m.Lock()
+	img.scale = mode
+	img.cache = nil
+	img.m.Unlock()
+	return img
+}
+
+func (img *_Image) SetSize(width, height int) *_Image {
+	img.width = width
+	img.height = height
+	return img
+}
+
+// Disabled disables (or enables) gesture of image
+func (img *_Image) Disabled(b bool) *_Image {
+	img.disabled = b
+	return img
+}
+
+func (img *_Image) Padding(sides ...int) *_Image {
+	img.padding = edgesOf(sides...)
+	return img
+}
+
+func (img *_Image) Margin(sides ...int) *_Image {
+	img.margin = edgesOf(sides...)
+	return img
+}
+
+func (img *_Image) Flex(weight int) *_Image {
+	img.setFlex(weight)
+	return img
+}
+
+func (img *_Image) LayoutPriority(priority int) *_Image {
+	img.setPriority(priority)
+	return img
+}
+
+func (img *_Image) MinWidth(width int) *_Image {
+	img.setWidthLimits(max(0, width), -1)
+	return img
+}
+
+func (img *_Image) MaxWidth(width int) *_Image {
+	img.setWidthLimits(-1, max(0, width))
+	return img
+}
+
+func (img *_Image) MinHeight(height int) *_Image {
+	img.setHeightLimits(max(0, height), -1)
+	return img
+}
+
+func (img *_Image) MaxHeight(height int) *_Image {
+	img.setHeightLimits(-1, max(0, height))
+	return img
+}
+
+func (img *_Image) Gesture(gesture Gesture) *_Image {
+	img.gestureFlag = true
+	img.gesture = gesture
+	return img
+}
+
+// gifFrames composes GIF frames (which can be partial) into full images
+// respecting disposal methods
+func gifFrames(g *gif.GIF) ([]image.Image, []time.Duration) {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	frames := make([]image.Image, 0, len(g.Image))
+	delays := make([]time.Duration, 0, len(g.Image))
+	current := image.NewRGBA(bounds)
+	for i, frame := range g.Image {
+		previous := image.NewRGBA(bounds)
+		draw.Draw(previous, bounds, current, image.Point{}, draw.Src)
+		draw.Draw(current, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		result := image.NewRGBA(bounds)
+		draw.Draw(result, bounds, current, image.Point{}, draw.Src)
+		frames = append(frames, result)
+		delay := 100 * time.Millisecond
+		if i < len(g.Delay) && g.Delay[i] > 1 {
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+		delays = append(delays, delay)
+		if i < len(g.Disposal) {
+			switch g.Disposal[i] {
+			case gif.DisposalBackground:
+				draw.Draw(current, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+			case gif.DisposalPrevious:
+				current = previous
+			}
+		}
+	}
+	return frames, delays
+}
+
+func newImage(frames []image.Image, delays []time.Duration) *_Image {
+	img := new(_Image)
+	img.frames = frames
+	img.delays = delays
+	img.scale = ScaleFit
+	img.playing = true
+	img.width, img.height = -1, -1
+	if len(frames) > 0 {
+		bounds := frames[0].Bounds()
+		img.width = bounds.Dx()
+		img.height = (bounds.Dy() + 1) / 2
+	}
+	return img
+}
+
+// Image creates view showing image. By default view takes natural image
+// size: one cell per pixel horizontally and two pixels per cell vertically.
+func Image(source image.Image) *_Image {
+	return newImage([]image.Image{source}, []time.Duration{0})
+}
+
+// ImageFromReader decodes PNG, JPEG or GIF image. All frames of animated GIF
+// are decoded.
+func ImageFromReader(r io.Reader) (*_Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format == "gif" {
+		decoded, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		frames, delays := gifFrames(decoded)
+		img := newImage(frames, delays)
+		img.loopCount = decoded.LoopCount
+		return img, nil
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return Image(decoded), nil
+}
+
+// ImageFromFile decodes PNG, JPEG or GIF image file
+func ImageFromFile(path string) (*_Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ImageFromReader(file)
+}
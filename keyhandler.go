@@ -2,7 +2,12 @@ package fwsui
 
 import proto "github.com/Nekhaevalex/fwsprotocol"
 
+// KeyHandler – implemented by views that can get keyboard focus. Window
+// passes key events to focused view, keys that view doesn't handle (returns
+// false) are passed to containers implementing KeyHandler it is placed in
+// and then handled by window: Tab moves focus to the next view.
 type KeyHandler interface {
-	getInputChan() chan *proto.EventRequest
-	enableInput()
+	canFocus() bool
+	handleKey(event *proto.EventRequest) bool
+	focusChanged(focused bool)
 }
@@ -0,0 +1,389 @@
+package fwsui
+
+import (
+	"sort"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+)
+
+// Layout is done in two passes. Measure pass asks views which sizes they
+// accept for proposed size, arrange pass gives every child its frame inside
+// container, after that children are rendered in their frames.
+
+// unbounded – maximal size of views that can grow infinitely
+const unbounded = 1 << 30
+
+// sizeRange – sizes view accepts along one axis
+type sizeRange struct {
+	min, ideal, max int
+}
+
+// fixedSize returns range of view that has exactly one size
+func fixedSize(size int) sizeRange {
+	size = max(0, size)
+	return sizeRange{size, size, size}
+}
+
+// shrinkableSize returns range of view that ideally takes idealSize and can
+// be made smaller, but doesn't grow
+func shrinkableSize(idealSize int) sizeRange {
+	idealSize = max(0, idealSize)
+	return sizeRange{0, idealSize, idealSize}
+}
+
+// flexibleSize returns range of view that can take any space not smaller
+// than minSize
+func flexibleSize(minSize, idealSize int) sizeRange {
+	minSize = max(0, minSize)
+	return sizeRange{minSize, max(minSize, idealSize), unbounded}
+}
+
+// fixed reports whether view has exactly one size
+func (r sizeRange) fixed() bool {
+	return r.min >= r.max
+}
+
+// clamp returns size closest to proposed one that view accepts. Negative
+// proposal means that view takes its ideal size.
+func (r sizeRange) clamp(size int) int {
+	if size < 0 {
+		return r.ideal
+	}
+	return max(r.min, min(r.max, size))
+}
+
+// add returns range grown by size (unbounded stays unbounded)
+func (r sizeRange) add(size int) sizeRange {
+	result := sizeRange{max(0, r.min+size), max(0, r.ideal+size), r.max}
+	if r.max < unbounded {
+		result.max = max(0, r.max+size)
+	}
+	return result
+}
+
+// measurer – implemented by views that measure their content. Proposed size
+// is the size parent is going to give view along axis, or -1 if it's not
+// known yet (view reports its ideal size then).
+type measurer interface {
+	measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange)
+}
+
+// measureView measures view with its padding and margin. Views without
+// measurer are measured by logical size: fixed sizes stay fixed and floating
+// sizes can take any space. Size limits of view are applied to its content.
+func measureView(v View, proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	margin, padding := insetsOf(v)
+	insetW := margin.horizontal() + padding.horizontal()
+	insetH := margin.vertical() + padding.vertical()
+	inner := func(proposal, inset int) int {
+		if proposal < 0 {
+			return proposal
+		}
+		return max(0, proposal-inset)
+	}
+	var width, height sizeRange
+	if asserted, ok := v.(measurer); ok {
+		width, height = asserted.measure(inner(proposedWidth, insetW), inner(proposedHeight, insetH))
+	} else {
+		logicalWidth, logicalHeight := v.getLogicalSize()
+		width, height = logicalRange(logicalWidth), logicalRange(logicalHeight)
+	}
+	if asserted, ok := v.(layoutParams); ok {
+		width, height = asserted.layoutModifiers().limit(width, height)
+	}
+	return width.add(insetW), height.add(insetH)
+}
+
+// logicalRange returns range of logical size: negative (floating) size can
+// be anything, other sizes are fixed
+func logicalRange(size int) sizeRange {
+	if size < 0 {
+		return flexibleSize(0, 0)
+	}
+	return fixedSize(size)
+}
+
+// frameOf measures view in area of given size and returns size view takes
+// in it. Height is measured for the width view gets, so wrapped text gets
+// enough lines.
+func frameOf(v View, width, height int) (int, int) {
+	widthRange, _ := measureView(v, width, height)
+	w := widthRange.clamp(width)
+	_, heightRange := measureView(v, w, height)
+	return w, heightRange.clamp(height)
+}
+
+// layoutItem – child of stack measured along main axis of stack
+type layoutItem struct {
+	size     sizeRange
+	weight   int // share of free space, 0 – doesn't grow
+	priority int // items with higher priority get space first
+}
+
+// layoutable – layout modifiers of view. Embedded into views, zero value
+// means no modifiers. Minimal and maximal sizes limit size of view content,
+// view with maximal size can grow up to it. Flex weight and priority define
+// how free space of stack is shared, view with them grows along stack even
+// if its size is fixed.
+type layoutable struct {
+	minWidth, maxWidth   int
+	minHeight, maxHeight int
+	widthSet, heightSet  bool // size limits are set along axis
+	weight               int
+	priority             int
+	grows                bool // flex weight or priority is set
+}
+
+// layoutParams – implemented by views with layout modifiers
+type layoutParams interface {
+	layoutModifiers() *layoutable
+}
+
+// layoutModifiers implements layoutParams.
+func (l *layoutable) layoutModifiers() *layoutable {
+	return l
+}
+
+// setWidthLimits replaces limits of width that aren't negative
+func (l *layoutable) setWidthLimits(minWidth, maxWidth int) {
+	if !l.widthSet {
+		l.widthSet = true
+		l.maxWidth = unbounded
+	}
+	if minWidth >= 0 {
+		l.minWidth = minWidth
+	}
+	if maxWidth >= 0 {
+		l.maxWidth = maxWidth
+	}
+}
+
+// setHeightLimits replaces limits of height that aren't negative
+func (l *layoutable) setHeightLimits(minHeight, maxHeight int) {
+	if !l.heightSet {
+		l.heightSet = true
+		l.maxHeight = unbounded
+	}
+	if minHeight >= 0 {
+		l.minHeight = minHeight
+	}
+	if maxHeight >= 0 {
+		l.maxHeight = maxHeight
+	}
+}
+
+// setFlex sets flex weight (negative is treated as zero)
+func (l *layoutable) setFlex(weight int) {
+	l.grows = true
+	l.weight = max(0, weight)
+}
+
+// setPriority sets layout priority, flex weight stays 1 if it isn't set
+func (l *layoutable) setPriority(priority int) {
+	if !l.grows {
+		l.grows = true
+		l.weight = 1
+	}
+	l.priority = priority
+}
+
+// limit applies size limits to measured content size
+func (l *layoutable) limit(width, height sizeRange) (sizeRange, sizeRange) {
+	if l.widthSet {
+		width = limitRange(width, l.minWidth, l.maxWidth)
+	}
+	if l.heightSet {
+		height = limitRange(height, l.minHeight, l.maxHeight)
+	}
+	return width, height
+}
+
+// limitRange returns range limited by minimal and maximal sizes. Range
+// grows up to maximal size if it is set, minimal size wins over maximal one.
+func limitRange(r sizeRange, minSize, maxSize int) sizeRange {
+	if maxSize < unbounded {
+		r.max = maxSize
+	}
+	r.min = max(minSize, min(r.min, maxSize))
+	r.max = max(r.min, min(r.max, maxSize))
+	r.ideal = max(r.min, min(r.max, r.ideal))
+	return r
+}
+
+// layoutItemOf returns layout item of view measured along horizontal or
+// vertical axis. Views without layout modifiers grow with weight 1 and
+// priority 0.
+func layoutItemOf(v View, size sizeRange, horizontal bool) layoutItem {
+	item := layoutItem{size: size, weight: 1}
+	if asserted, ok := v.(layoutParams); ok {
+		l := asserted.layoutModifiers()
+		if l.grows {
+			item.weight, item.priority = l.weight, l.priority
+			// Maximal size set along axis is already in measured range
+			maxSize := unbounded
+			if horizontal && l.widthSet {
+				maxSize = l.maxWidth
+			} else if !horizontal && l.heightSet {
+				maxSize = l.maxHeight
+			}
+			if maxSize >= unbounded {
+				item.size.max = unbounded
+			}
+		}
+	}
+	return item
+}
+
+// distribute splits total space between items along one axis. Every item
+// gets at least its minimum. The rest is given to items by priority:
+// items with higher priority grow first (up to their maximums), items with
+// the same priority share space in proportion to their weights. Cells left
+// after integer division are given one by one to growing items in order.
+// Sizes are never negative; if there is not enough space items get their
+// minimums and overflow.
+func distribute(total int, items []layoutItem) []int {
+	sizes := make([]int, len(items))
+	levels := make([]int, 0)
+	for i, item := range items {
+		sizes[i] = item.size.min
+		found := false
+		for _, level := range levels {
+			found = found || level == item.priority
+		}
+		if !found {
+			levels = append(levels, item.priority)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(levels)))
+	for _, level := range levels {
+		group := make([]int, 0)
+		available := total
+		for i, item := range items {
+			if item.priority == level && !item.size.fixed() && item.weight > 0 {
+				group = append(group, i)
+			} else {
+				available -= sizes[i]
+			}
+		}
+		growItems(sizes, items, group, available)
+	}
+	return sizes
+}
+
+// growItems shares available space between group of items in proportion to
+// their weights respecting their minimums and maximums
+func growItems(sizes []int, items []layoutItem, group []int, available int) {
+	frozen := make(map[int]bool)
+	for {
+		space := available
+		totalWeight := 0
+		for _, i := range group {
+			if frozen[i] {
+				space -= sizes[i]
+			} else {
+				totalWeight += items[i].weight
+			}
+		}
+		if totalWeight == 0 {
+			return
+		}
+		space = max(0, space)
+		// Items that can't take their share are frozen at their limit and
+		// the rest is shared again. Items below minimum are frozen first,
+		// as they take space from others.
+		violated := false
+		for _, belowMin := range []bool{true, false} {
+			for _, i := range group {
+				if frozen[i] {
+					continue
+				}
+				share := space * items[i].weight / totalWeight
+				if (belowMin && share < items[i].size.min) || (!belowMin && share > items[i].size.max) {
+					sizes[i] = max(items[i].size.min, min(items[i].size.max, share))
+					frozen[i] = true
+					violated = true
+				}
+			}
+			if violated {
+				break
+			}
+		}
+		if violated {
+			continue
+		}
+		left := space
+		for _, i := range group {
+			if !frozen[i] {
+				sizes[i] = space * items[i].weight / totalWeight
+				left -= sizes[i]
+			}
+		}
+		for _, i := range group {
+			if left == 0 {
+				break
+			}
+			if !frozen[i] && sizes[i] < items[i].size.max {
+				sizes[i]++
+				left--
+			}
+		}
+		return
+	}
+}
+
+// spacingSize returns space taken by spacing between count items
+func spacingSize(count, spacing int) int {
+	return max(0, count-1) * spacing
+}
+
+// alignOffset returns offset of item of given size aligned in space
+func alignOffset(a Align, space, size int) int {
+	switch a {
+	case Center:
+		return space/2 - size/2
+	case Right:
+		return space - size
+	}
+	return 0
+}
+
+// placeView renders view in frame given by its container: margin is left
+// empty, padding is filled with background of view and content is rendered
+// inside it. Position of view is set to position of its content.
+func placeView(canvas [][]proto.Cell, v View, x, y, width, height int) {
+	margin, padding := insetsOf(v)
+	x, y = x+margin.left, y+margin.top
+	width, height = max(0, width-margin.horizontal()), max(0, height-margin.vertical())
+	if asserted, ok := v.(backgroundView); ok && padding != (edges{}) {
+		bg := asserted.backgroundColor()
+		for ix := max(0, x); ix < min(len(canvas), x+width); ix++ {
+			for iy := max(0, y); iy < min(len(canvas[ix]), y+height); iy++ {
+				canvas[ix][iy] = proto.Cell{Ch: ' ', Bg: bg}
+			}
+		}
+	}
+	x, y = x+padding.left, y+padding.top
+	width, height = max(0, width-padding.horizontal()), max(0, height-padding.vertical())
+	v.setPos(x, y)
+	drawView(canvas, v, x, y, width, height)
+}
+
+// hitArea returns position and size of area of view that gets its gesture:
+// content with padding
+func hitArea(v View) (int, int, int, int) {
+	_, padding := insetsOf(v)
+	x, y := v.getPos()
+	width, height := v.getActualSize()
+	return x - padding.left, y - padding.top, width + padding.horizontal(), height + padding.vertical()
+}
+
+// drawView renders view in frame and copies it to canvas clipping parts
+// outside of canvas
+func drawView(canvas [][]proto.Cell, v View, x, y, width, height int) {
+	frame := v.render(width, height)
+	for ix := max(0, x); ix < min(len(canvas), x+width); ix++ {
+		for iy := max(0, y); iy < min(len(canvas[ix]), y+height); iy++ {
+			canvas[ix][iy] = frame[ix-x][iy-y]
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package fwsui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistribute(t *testing.T) {
+	flexible := func(weight, priority int) layoutItem {
+		return layoutItem{size: flexibleSize(0, 0), weight: weight, priority: priority}
+	}
+	limited := func(minSize, maxSize, weight, priority int) layoutItem {
+		return layoutItem{size: sizeRange{minSize, minSize, maxSize}, weight: weight, priority: priority}
+	}
+	fixed := func(size int) layoutItem {
+		return layoutItem{size: fixedSize(size), weight: 1}
+	}
+	tests := []struct {
+		name  string
+		total int
+		items []layoutItem
+		want  []int
+	}{
+		{"equal weights", 10, []layoutItem{flexible(1, 0), flexible(1, 0)}, []int{5, 5}},
+		{"weights", 100, []layoutItem{flexible(3, 0), flexible(1, 0)}, []int{75, 25}},
+		{"fixed item", 100, []layoutItem{fixed(10), flexible(1, 0)}, []int{10, 90}},
+		{"remainder cells", 10, []layoutItem{flexible(1, 0), flexible(1, 0), flexible(1, 0)}, []int{4, 3, 3}},
+		{"remainder skips item at maximum", 11, []layoutItem{limited(0, 3, 1, 0), flexible(1, 0), flexible(1, 0)}, []int{3, 4, 4}},
+		{"maximum freezes item", 100, []layoutItem{limited(0, 20, 1, 0), flexible(1, 0)}, []int{20, 80}},
+		{"minimum freezes item", 10, []layoutItem{limited(8, unbounded, 1, 0), flexible(1, 0)}, []int{8, 2}},
+		{"minimum and maximum", 30, []layoutItem{limited(12, unbounded, 1, 0), limited(0, 5, 1, 0), flexible(1, 0)}, []int{12, 5, 13}},
+		{"priority grows first", 100, []layoutItem{flexible(1, 0), limited(0, 30, 1, 1)}, []int{70, 30}},
+		{"priority takes all", 100, []layoutItem{flexible(1, 0), flexible(1, 1)}, []int{0, 100}},
+		{"lower priority keeps minimum", 10, []layoutItem{limited(4, unbounded, 1, 0), flexible(1, 1)}, []int{4, 6}},
+		{"zero weight doesn't grow", 10, []layoutItem{limited(2, unbounded, 0, 0), flexible(1, 0)}, []int{2, 8}},
+		{"overflow", 15, []layoutItem{fixed(10), fixed(10), flexible(1, 0)}, []int{10, 10, 0}},
+		{"no space", 0, []layoutItem{flexible(1, 0), limited(3, unbounded, 1, 0)}, []int{0, 3}},
+		{"no items", 10, nil, []int{}},
+	}
+	for _, test := range tests {
+		if got := distribute(test.total, test.items); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: distribute(%d) = %v, want %v", test.name, test.total, got, test.want)
+		}
+	}
+}
+
+func TestSizeRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		r         sizeRange
+		proposed  int
+		want      int
+		wantFixed bool
+	}{
+		{"fixed", fixedSize(5), 10, 5, true},
+		{"negative fixed", fixedSize(-3), 10, 0, true},
+		{"flexible without proposal", flexibleSize(2, 4), -1, 4, false},
+		{"flexible below minimum", flexibleSize(2, 4), 1, 2, false},
+		{"flexible grows", flexibleSize(2, 4), 50, 50, false},
+		{"shrinkable", shrinkableSize(6), 3, 3, false},
+		{"shrinkable doesn't grow", shrinkableSize(6), 50, 6, false},
+	}
+	for _, test := range tests {
+		if got := test.r.clamp(test.proposed); got != test.want || test.r.fixed() != test.wantFixed {
+			t.Errorf("%s: clamp(%d) = %d, fixed %v, want %d, %v", test.name, test.proposed, got, test.r.fixed(), test.want, test.wantFixed)
+		}
+	}
+}
+
+func TestLayoutModifiers(t *testing.T) {
+	widths := func(views ...View) []int {
+		HStack(views...).render(100, 1)
+		result := make([]int, len(views))
+		for i, v := range views {
+			result[i], _ = v.getActualSize()
+		}
+		return result
+	}
+	tests := []struct {
+		name  string
+		views []View
+		want  []int
+	}{
+		{"box flex", []View{Box(Text("content")).Flex(3), Box(Text("details")).Flex(1)}, []int{75, 25}},
+		{"text flex", []View{Text("content").Flex(1), Text("details")}, []int{93, 7}},
+		{"maximal width", []View{Text("content").MaxWidth(20), Box(nil).SetSize(-1, 1)}, []int{20, 80}},
+		{"minimal width", []View{Text("content").MinWidth(10)}, []int{10}},
+		{"priority", []View{Box(Text("a")).LayoutPriority(1).MaxWidth(30), Box(nil).SetSize(-1, 1)}, []int{30, 70}},
+		{"box size", []View{Box(Text("content")).SetSize(12, 1), Box(nil).SetSize(-1, 1)}, []int{12, 88}},
+		{"floating box", []View{Box(Text("content")).SetSize(-1, 1), Box(Text("details")).SetSize(-1, 1)}, []int{50, 50}},
+	}
+	for _, test := range tests {
+		if got := widths(test.views...); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: widths = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNilChildren(t *testing.T) {
+	tests := []struct {
+		name string
+		view View
+	}{
+		{"grid", Grid(FixedTrack(3)).AddView(nil, 0, 0)},
+		{"absolute layout", AbsoluteLayout(Place(nil))},
+		{"split", HSplit(nil, Text("a"))},
+		{"split with inserted view", VSplit(Text("a")).AddView(nil)},
+		{"tabs", Tabs(Tab("a", nil))},
+	}
+	for _, test := range tests {
+		if lines := canvasLines(test.view.render(10, 2)); len(lines) != 2 {
+			t.Errorf("%s with nil child renders %d lines, want 2", test.name, len(lines))
+		}
+	}
+}
+
+func TestEdgesOf(t *testing.T) {
+	tests := []struct {
+		sides []int
+		want  edges
+	}{
+		{nil, edges{}},
+		{[]int{2}, edges{2, 2, 2, 2}},
+		{[]int{1, 2}, edges{1, 2, 1, 2}},
+		{[]int{1, 2, 3}, edges{1, 2, 3, 2}},
+		{[]int{1, 2, 3, 4}, edges{1, 2, 3, 4}},
+		{[]int{1, 2, 3, 4, 5}, edges{1, 2, 3, 4}},
+		{[]int{-1, 2}, edges{0, 2, 0, 2}},
+	}
+	for _, test := range tests {
+		sides := append([]int(nil), test.sides...)
+		if got := edgesOf(sides...); got != test.want {
+			t.Errorf("edgesOf(%v) = %+v, want %+v", test.sides, got, test.want)
+		}
+		if !reflect.DeepEqual(sides, test.sides) {
+			t.Errorf("edgesOf(%v) changes its arguments to %v", test.sides, sides)
+		}
+	}
+}
@@ -0,0 +1,622 @@
+package fwsui
+
+import (
+	"sort"
+	"time"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+	"github.com/nsf/termbox-go"
+)
+
+// ListDataSource – data shown by List. Source also implements ListRowSource
+// to show rows as views or ListCellRenderer to draw rows directly.
+type ListDataSource interface {
+	Count() int
+}
+
+// ListRowSource – data source building view for row. Views are built only
+// for visible rows and kept while rows stay visible.
+type ListRowSource interface {
+	ListDataSource
+	Row(index int) View
+}
+
+// ListCellRenderer – data source drawing rows without views, the cheapest
+// way to show large amount of rows. Row background is already filled with
+// selection color when row is selected.
+type ListCellRenderer interface {
+	ListDataSource
+	DrawRow(p *_Painter, index int, selected bool)
+}
+
+// SelectionMode – how many rows of list can be selected
+type SelectionMode uint8
+
+const (
+	SingleSelection   SelectionMode = iota // Selection follows the current row
+	MultipleSelection                      // Rows are toggled, drag and Alt+arrows select ranges
+	NoSelection
+)
+
+// _List – view showing rows of data source one under another. Only visible
+// rows are built and drawn, so list can show any number of rows.
+type _List struct {
+	x, y, width, height int
+	awidth, aheight     int
+	source              ListDataSource
+	rowHeight           int
+	offset              int // index of the first visible row
+	cursor              int // current row moved by keyboard
+	anchor              int // row range selection starts from
+	selected            map[int]bool
+	mode                SelectionMode
+	rows                map[int]View // views of visible rows
+	showBar             bool
+	bar                 *_ScrollBar
+	wheel               *wheelGesture
+	drag                *_DragGesture
+	dragging            bool
+	lastClick           time.Time
+	lastClickRow        int
+	focused             bool
+	onSelectionChanged  func(selected []int)
+	onActivate          func(index int)
+	onDoubleClick       func(index int)
+	theme               *Theme
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
+}
+
+// getGesture implements View.
+func (*_List) getGesture() Gesture {
+	return nil
+}
+
+// hasGesture implements View.
+func (*_List) hasGesture() bool {
+	return false
+}
+
+func (list *_List) getLogicalSize() (int, int) {
+	return list.width, list.height
+}
+
+func (list *_List) getActualSize() (int, int) {
+	return list.awidth, list.aheight
+}
+
+func (list *_List) setPos(x, y int) {
+	list.x = x
+	list.y = y
+}
+
+func (list *_List) getPos() (int, int) {
+	return list.x, list.y
+}
+
+// setTheme implements themedView.
+func (list *_List) setTheme(theme *Theme) {
+	list.theme = theme
+}
+
+func (list *_List) count() int {
+	if list.source == nil {
+		return 0
+	}
+	return list.source.Count()
+}
+
+// page returns number of rows fully shown in list
+func (list *_List) page() int {
+	return max(1, list.aheight/list.rowHeight)
+}
+
+// rowAt returns index of row at y of list content
+func (list *_List) rowAt(y int) int {
+	if y < 0 {
+		return list.offset - 1
+	}
+	return list.offset + y/list.rowHeight
+}
+
+// scrollBy moves visible rows keeping them inside the list
+func (list *_List) scrollBy(delta int) {
+	list.offset = max(0, min(list.offset+delta, list.count()-list.page()))
+}
+
+// ScrollTo scrolls list so that row at index is visible
+func (list *_List) ScrollTo(index int) *_List {
+	if index < list.offset {
+		list.offset = max(0, index)
+	} else if index >= list.offset+list.page() {
+		list.offset = index - list.page() + 1
+	}
+	return list
+}
+
+// setSelection replaces selected rows and reports change
+func (list *_List) setSelection(selected map[int]bool) {
+	changed := len(selected) != len(list.selected)
+	for index := range selected {
+		if !list.selected[index] {
+			changed = true
+		}
+	}
+	list.selected = selected
+	if changed && list.onSelectionChanged != nil {
+		list.onSelectionChanged(list.Selected())
+	}
+}
+
+// selectRange selects rows between anchor and index
+func (list *_List) selectRange(index int) {
+	selected := make(map[int]bool)
+	for i := min(list.anchor, index); i <= max(list.anchor, index); i++ {
+		selected[i] = true
+	}
+	list.setSelection(selected)
+}
+
+// toggle adds row to selection or removes it
+func (list *_List) toggle(index int) {
+	selected := make(map[int]bool, len(list.selected)+1)
+	for i := range list.selected {
+		selected[i] = true
+	}
+	if selected[index] {
+		delete(selected, index)
+	} else {
+		selected[index] = true
+	}
+	list.setSelection(selected)
+}
+
+// moveCursor makes row at index current. Single selection follows the
+// current row, multiple selection is extended from anchor if extend is set.
+func (list *_List) moveCursor(index int, extend bool) {
+	count := list.count()
+	if count == 0 {
+		return
+	}
+	list.cursor = max(0, min(index, count-1))
+	list.ScrollTo(list.cursor)
+	switch list.mode {
+	case SingleSelection:
+		list.anchor = list.cursor
+		list.setSelection(map[int]bool{list.cursor: true})
+	case MultipleSelection:
+		if extend {
+			list.selectRange(list.cursor)
+		} else {
+			list.anchor = list.cursor
+		}
+	}
+}
+
+// activate calls activation action for row
+func (list *_List) activate(index int) {
+	if list.onActivate != nil && index >= 0 && index < list.count() {
+		list.onActivate(index)
+	}
+}
+
+// press handles mouse press on row: row becomes current, multiple selection
+// toggles it. Second click on the same row is double click.
+func (list *_List) press(index int) {
+	if list.host != nil && list.canFocus() {
+		list.host.setFocus(list)
+	}
+	if index < 0 || index >= list.count() {
+		return
+	}
+	now := time.Now()
+	if index == list.lastClickRow && now.Sub(list.lastClick) < doubleClickInterval {
+		list.lastClick = time.Time{}
+		if list.onDoubleClick != nil {
+			list.onDoubleClick(index)
+		}
+		list.activate(index)
+		return
+	}
+	list.lastClick, list.lastClickRow = now, index
+	list.cursor = index
+	list.anchor = index
+	switch list.mode {
+	case SingleSelection:
+		list.setSelection(map[int]bool{index: true})
+	case MultipleSelection:
+		list.toggle(index)
+	}
+}
+
+func (list *_List) render(width, height int) [][]proto.Cell {
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := list.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
+	list.awidth = width
+	list.aheight = height
+	count := list.count()
+	list.showBar = count*list.rowHeight > height
+	rowsWidth := width
+	if list.showBar {
+		rowsWidth = max(0, width-1)
+	}
+	list.scrollBy(0)
+	list.cursor = max(0, min(list.cursor, count-1))
+	theme := themeOrDefault(list.theme)
+	_, background := list.style.colors(theme.Text, proto.Color{}, list.theme)
+	canvas := allocateCanvas(width, height)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			canvas[x][y] = proto.Cell{Ch: ' ', Bg: background}
+		}
+	}
+	shown := make(map[int]View)
+	rowsLayer := allocateCanvas(width, height)
+	for index := list.offset; index < count && (index-list.offset)*list.rowHeight < height; index++ {
+		y := (index - list.offset) * list.rowHeight
+		rowHeight := min(list.rowHeight, height-y)
+		row := &_Painter{cells: make([][]proto.Cell, rowsWidth), width: rowsWidth, height: rowHeight, theme: list.theme}
+		for x := 0; x < rowsWidth; x++ {
+			row.cells[x] = canvas[x][y : y+rowHeight]
+		}
+		switch {
+		case list.selected[index]:
+			row.Fill(0, 0, rowsWidth, rowHeight, theme.Selection)
+		case list.focused && index == list.cursor && list.mode != SingleSelection:
+			current := theme.Selection
+			current.A = 96
+			row.Fill(0, 0, rowsWidth, rowHeight, current)
+		}
+		switch source := list.source.(type) {
+		case ListCellRenderer:
+			source.DrawRow(row, index, list.selected[index])
+		case ListRowSource:
+			view, ok := list.rows[index]
+			if !ok {
+				view = source.Row(index)
+			}
+			shown[index] = view
+			placeView(rowsLayer, view, 0, y, rowsWidth, list.rowHeight)
+		}
+	}
+	if len(shown) > 0 {
+		for x := 0; x < rowsWidth; x++ {
+			for y := 0; y < height; y++ {
+				canvas[x][y] = rowsLayer[x][y].Over(canvas[x][y])
+			}
+		}
+	}
+	list.rows = shown
+	if list.showBar {
+		thumbColor := theme.SecondaryText
+		if list.focused {
+			thumbColor = theme.Accent
+		}
+		list.bar.offset, list.bar.content, list.bar.viewport = list.offset, count, list.page()
+		list.bar.thumbColor, list.bar.bg = thumbColor, background
+		list.bar.setPos(rowsWidth, 0)
+		drawView(canvas, list.bar, rowsWidth, 0, width-rowsWidth, height)
+	}
+	fixWideCells(canvas)
+	return canvas
+}
+
+// measure implements measurer. List takes all space it is given and ideally
+// shows all its rows.
+func (list *_List) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	width, height := flexibleSize(0, 0), flexibleSize(0, list.count()*list.rowHeight)
+	if list.width >= 0 {
+		width = fixedSize(list.width)
+	}
+	if list.height >= 0 {
+		height = fixedSize(list.height)
+	}
+	return width, height
+}
+
+// subviews returns views of visible rows
+func (list *_List) subviews() []View {
+	indexes := make([]int, 0, len(list.rows))
+	for index := range list.rows {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	views := make([]View, len(indexes))
+	for i, index := range indexes {
+		views[i] = list.rows[index]
+	}
+	return views
+}
+
+// getChildrenGestures returns wheel gesture of list passing clicks and drags
+// to rows gesture, gestures of visible row views and scroll bar gesture
+func (list *_List) getChildrenGestures(x, y int) []GestureDescriptor {
+	list.drag.setParentViewSizes(list)
+	list.drag.getGestureDescriptor(x, y)
+	x, y = x+list.x, y+list.y
+	list.wheel.x, list.wheel.y = 0, 0
+	list.wheel.width, list.wheel.height = list.awidth, list.aheight
+	actors := []GestureDescriptor{list.wheel.getGestureDescriptor(x, y)}
+	area := GestureDescriptor{x: x, y: y, width: list.awidth, height: list.aheight}
+	for _, child := range list.subviews() {
+		if !viewEnabled(child) {
+			continue
+		}
+		childActors := make([]GestureDescriptor, 0)
+		if child.hasGesture() {
+			childActors = append(childActors, child.getGesture().getGestureDescriptor(x, y))
+		}
+		if asserted, ok := child.(Container); ok {
+			childActors = append(childActors, asserted.getChildrenGestures(x, y)...)
+		}
+		for _, actor := range childActors {
+			if clipped, ok := clipArea(actor, area); ok {
+				actors = append(actors, clipped)
+			}
+		}
+	}
+	if list.showBar {
+		actors = append(actors, list.bar.getGesture().getGestureDescriptor(x, y))
+	}
+	return actors
+}
+
+// canFocus implements KeyHandler.
+func (list *_List) canFocus() bool {
+	return !list.disabled && list.count() > 0
+}
+
+// handleKey implements KeyHandler. Arrows, PgUp/PgDn and Home/End move the
+// current row (with Alt multiple selection is extended), Space toggles the
+// current row and Enter activates it.
+func (list *_List) handleKey(event *proto.EventRequest) bool {
+	count := list.count()
+	if count == 0 {
+		return false
+	}
+	extend := event.Mod == termbox.ModAlt
+	if event.Ch == ' ' || (event.Ch == 0 && event.Key == termbox.KeySpace) {
+		switch list.mode {
+		case SingleSelection:
+			list.moveCursor(list.cursor, false)
+		case MultipleSelection:
+			list.anchor = list.cursor
+			list.toggle(list.cursor)
+		}
+		return true
+	}
+	if event.Ch != 0 {
+		return false
+	}
+	switch event.Key {
+	case termbox.KeyArrowUp:
+		list.moveCursor(list.cursor-1, extend)
+	case termbox.KeyArrowDown:
+		list.moveCursor(list.cursor+1, extend)
+	case termbox.KeyPgup:
+		if extend {
+			return false
+		}
+		list.moveCursor(list.cursor-list.page(), false)
+	case termbox.KeyPgdn:
+		if extend {
+			return false
+		}
+		list.moveCursor(list.cursor+list.page(), false)
+	case termbox.KeyHome:
+		list.moveCursor(0, extend)
+	case termbox.KeyEnd:
+		list.moveCursor(count-1, extend)
+	case termbox.KeyEnter:
+		list.activate(list.cursor)
+	default:
+		return false
+	}
+	return true
+}
+
+// focusChanged implements KeyHandler.
+func (list *_List) focusChanged(focused bool) {
+	list.focused = focused
+}
+
+// Selected returns indexes of selected rows in ascending order
+func (list *_List) Selected() []int {
+	indexes := make([]int, 0, len(list.selected))
+	for index := range list.selected {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// Select replaces selection with rows at indexes, the first of them becomes
+// the current row
+func (list *_List) Select(indexes ...int) *_List {
+	selected := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		if index >= 0 && index < list.count() && list.mode != NoSelection {
+			selected[index] = true
+		}
+	}
+	if len(indexes) > 0 {
+		list.cursor, list.anchor = indexes[0], indexes[0]
+	}
+	list.setSelection(selected)
+	list.invalidate()
+	return list
+}
+
+// Reload drops views of rows and selection of rows that don't exist anymore,
+// should be called after data of source changes
+func (list *_List) Reload() *_List {
+	count := list.count()
+	list.rows = nil
+	selected := make(map[int]bool, len(list.selected))
+	for index := range list.selected {
+		if index < count {
+			selected[index] = true
+		}
+	}
+	list.setSelection(selected)
+	list.cursor = max(0, min(list.cursor, count-1))
+	list.invalidate()
+	return list
+}
+
+// RowHeight sets height of every row (1 by default)
+func (list *_List) RowHeight(height int) *_List {
+	list.rowHeight = max(1, height)
+	return list
+}
+
+// SelectionMode sets how many rows can be selected
+func (list *_List) SelectionMode(mode SelectionMode) *_List {
+	list.mode = mode
+	list.setSelection(map[int]bool{})
+	return list
+}
+
+// OnSelectionChanged sets action called with selected rows when selection
+// changes
+func (list *_List) OnSelectionChanged(action func(selected []int)) *_List {
+	list.onSelectionChanged = action
+	return list
+}
+
+// OnActivate sets action called when row is activated with Enter or double
+// click
+func (list *_List) OnActivate(action func(index int)) *_List {
+	list.onActivate = action
+	return list
+}
+
+// OnDoubleClick sets action called on double click on row (before
+// activation action)
+func (list *_List) OnDoubleClick(action func(index int)) *_List {
+	list.onDoubleClick = action
+	return list
+}
+
+func (list *_List) SetSize(width, height int) *_List {
+	list.width = width
+	list.height = height
+	return list
+}
+
+// Disabled disables (or enables) list
+func (list *_List) Disabled(b bool) *_List {
+	list.disabled = b
+	return list
+}
+
+// StyleClass sets stylesheet classes of list
+func (list *_List) StyleClass(classes ...string) *_List {
+	list.classes = classes
+	return list
+}
+
+func (list *_List) Padding(sides ...int) *_List {
+	list.padding = edgesOf(sides...)
+	return list
+}
+
+func (list *_List) Margin(sides ...int) *_List {
+	list.margin = edgesOf(sides...)
+	return list
+}
+
+func (list *_List) Flex(weight int) *_List {
+	list.setFlex(weight)
+	return list
+}
+
+func (list *_List) LayoutPriority(priority int) *_List {
+	list.setPriority(priority)
+	return list
+}
+
+func (list *_List) MinWidth(width int) *_List {
+	list.setWidthLimits(max(0, width), -1)
+	return list
+}
+
+func (list *_List) MaxWidth(width int) *_List {
+	list.setWidthLimits(-1, max(0, width))
+	return list
+}
+
+func (list *_List) MinHeight(height int) *_List {
+	list.setHeightLimits(max(0, height), -1)
+	return list
+}
+
+func (list *_List) MaxHeight(height int) *_List {
+	list.setHeightLimits(-1, max(0, height))
+	return list
+}
+
+// List creates list showing rows of data source, e.g. log with 50 000 lines:
+//
+//	type logSource []string
+//
+//	func (log logSource) Count() int { return len(log) }
+//	func (log logSource) DrawRow(p *_Painter, index int, selected bool) {
+//		p.Text(0, 0, log[index], p.Theme().Text)
+//	}
+//
+//	List(logSource(lines)).OnActivate(func(index int) { open(lines[index]) })
+func List(source ListDataSource) *_List {
+	list := new(_List)
+	list.source = source
+	list.kind = "List"
+	list.width = -1
+	list.height = -1
+	list.rowHeight = 1
+	list.selected = make(map[int]bool)
+	list.lastClickRow = -1
+	list.bar = newScrollBar(true, func(offset int) {
+		list.scrollBy(offset - list.offset)
+	})
+	list.wheel = &wheelGesture{action: func(delta int) {
+		list.scrollBy(delta)
+	}}
+	list.drag = DragGesture().OnChanged(func(value Value) {
+		_, localY := value.LocalLocation()
+		_, padding := insetsOf(list)
+		localY -= padding.top
+		if !list.dragging {
+			list.dragging = true
+			list.press(list.rowAt(localY))
+			return
+		}
+		if _, translationY := value.Translation(); translationY == 0 {
+			return
+		}
+		// Dragging out of list scrolls it
+		if localY < 0 {
+			list.scrollBy(-1)
+		} else if localY >= list.aheight {
+			list.scrollBy(1)
+		}
+		index := max(0, min(list.rowAt(max(0, min(localY, list.aheight-1))), list.count()-1))
+		switch list.mode {
+		case SingleSelection:
+			if index != list.cursor {
+				list.moveCursor(index, false)
+			}
+		case MultipleSelection:
+			list.cursor = index
+			list.selectRange(index)
+		}
+	}).OnEnded(func(value Value) {
+		list.dragging = false
+	})
+	list.wheel.setAltGesture(list.drag)
+	return list
+}
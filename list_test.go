@@ -0,0 +1,107 @@
+package fwsui
+
+import (
+	"reflect"
+	"testing"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+	"github.com/nsf/termbox-go"
+)
+
+// linesSource – list data source drawing rows of text
+type linesSource []string
+
+func (lines linesSource) Count() int { return len(lines) }
+
+func (lines linesSource) DrawRow(p *_Painter, index int, selected bool) {
+	p.Text(0, 0, lines[index], p.Theme().Text)
+}
+
+func keyEvent(key termbox.Key, mod termbox.Modifier) *proto.EventRequest {
+	event := new(proto.EventRequest)
+	event.Type = termbox.EventKey
+	event.Key = key
+	event.Mod = mod
+	return event
+}
+
+func TestListEmptySource(t *testing.T) {
+	list := List(linesSource{})
+	list.render(10, 3)
+	list.scrollBy(5)
+	list.scrollBy(-5)
+	if list.offset != 0 {
+		t.Errorf("offset = %d, want 0", list.offset)
+	}
+	list.moveCursor(3, false)
+	list.moveCursor(-1, true)
+	if list.cursor != 0 {
+		t.Errorf("cursor = %d, want 0", list.cursor)
+	}
+	if selected := list.Selected(); len(selected) != 0 {
+		t.Errorf("selected = %v, want none", selected)
+	}
+	for _, key := range []termbox.Key{termbox.KeyArrowDown, termbox.KeyEnd, termbox.KeyPgdn, termbox.KeyEnter} {
+		if list.handleKey(keyEvent(key, 0)) {
+			t.Errorf("key %d is handled by empty list", key)
+		}
+	}
+	if list.canFocus() {
+		t.Error("empty list can get focus")
+	}
+	list.Reload()
+	if list.cursor != 0 || list.offset != 0 {
+		t.Errorf("cursor, offset = %d, %d after reload, want 0, 0", list.cursor, list.offset)
+	}
+}
+
+func TestListScrollBy(t *testing.T) {
+	list := List(make(linesSource, 10))
+	list.render(10, 4)
+	list.scrollBy(100)
+	if list.offset != 6 {
+		t.Errorf("offset = %d, want 6", list.offset)
+	}
+	list.scrollBy(-2)
+	if list.offset != 4 {
+		t.Errorf("offset = %d, want 4", list.offset)
+	}
+	list.scrollBy(-100)
+	if list.offset != 0 {
+		t.Errorf("offset = %d, want 0", list.offset)
+	}
+}
+
+func TestListMoveCursor(t *testing.T) {
+	list := List(make(linesSource, 10))
+	list.render(10, 4)
+	list.moveCursor(20, false)
+	if list.cursor != 9 || list.offset != 6 {
+		t.Errorf("cursor, offset = %d, %d, want 9, 6", list.cursor, list.offset)
+	}
+	if selected := list.Selected(); !reflect.DeepEqual(selected, []int{9}) {
+		t.Errorf("selected = %v, want [9]", selected)
+	}
+	list.moveCursor(-3, false)
+	if list.cursor != 0 || list.offset != 0 {
+		t.Errorf("cursor, offset = %d, %d, want 0, 0", list.cursor, list.offset)
+	}
+}
+
+func TestListExtendSelection(t *testing.T) {
+	var reported []int
+	list := List(make(linesSource, 10)).SelectionMode(MultipleSelection).OnSelectionChanged(func(selected []int) {
+		reported = selected
+	})
+	list.render(10, 4)
+	list.moveCursor(2, false)
+	list.handleKey(keyEvent(termbox.KeyArrowDown, termbox.ModAlt))
+	list.handleKey(keyEvent(termbox.KeyArrowDown, termbox.ModAlt))
+	if want := []int{2, 3, 4}; !reflect.DeepEqual(reported, want) {
+		t.Errorf("selected = %v, want %v", reported, want)
+	}
+	list.handleKey(keyEvent(termbox.KeySpace, 0))
+	if want := []int{2, 3}; !reflect.DeepEqual(list.Selected(), want) {
+		t.Errorf("selected = %v after toggle, want %v", list.Selected(), want)
+	}
+}
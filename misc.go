@@ -16,16 +16,183 @@ func allocateCanvas(width, height int) [][]proto.Cell {
 	return canvas
 }
 
-func viewSizeFloating(v View) (bool, bool) {
-	size_x, size_y := v.getLogicalSize()
-	can_x, can_y := false, false
-	if size_x < 0 {
-		can_x = true
+// walkViews calls fn for view and all views contained in it (depth first,
+// parents before children)
+func walkViews(v View, fn func(v View)) {
+	if v == nil {
+		return
 	}
-	if size_y < 0 {
-		can_y = true
+	fn(v)
+	if asserted, ok := v.(Container); ok {
+		for _, child := range asserted.subviews() {
+			walkViews(child, fn)
+		}
+	}
+}
+
+// disableable – enabled status of view. Embedded into views that can be
+// disabled.
+type disableable struct {
+	disabled bool
+}
+
+func (d *disableable) isDisabled() bool {
+	return d.disabled
+}
+
+// disabledView – implemented by views that can be disabled
+type disabledView interface {
+	isDisabled() bool
+}
+
+// viewEnabled reports whether view itself is not disabled
+func viewEnabled(v View) bool {
+	if asserted, ok := v.(disabledView); ok {
+		return !asserted.isDisabled()
+	}
+	return true
+}
+
+// walkEnabledViews is like walkViews, but also tells whether view is
+// enabled: view and all containers it is placed in are not disabled
+func walkEnabledViews(v View, enabled bool, fn func(v View, enabled bool)) {
+	if v == nil {
+		return
+	}
+	enabled = enabled && viewEnabled(v)
+	fn(v, enabled)
+	if asserted, ok := v.(Container); ok {
+		for _, child := range asserted.subviews() {
+			walkEnabledViews(child, enabled, fn)
+		}
 	}
-	return can_x, can_y
+}
+
+// edges – sizes of sides of view area
+type edges struct {
+	top, right, bottom, left int
+}
+
+// edgesOf returns edges set like in CSS: one value for all sides, two values
+// for vertical and horizontal sides, three for top, horizontal and bottom
+// sides and four for top, right, bottom and left sides. Values after the
+// fourth one are ignored, no values give zero edges. Negative sizes are
+// treated as zero.
+func edgesOf(sides ...int) edges {
+	clamped := make([]int, min(4, len(sides)))
+	for i := range clamped {
+		clamped[i] = max(0, sides[i])
+	}
+	switch len(clamped) {
+	case 1:
+		return edges{clamped[0], clamped[0], clamped[0], clamped[0]}
+	case 2:
+		return edges{clamped[0], clamped[1], clamped[0], clamped[1]}
+	case 3:
+		return edges{clamped[0], clamped[1], clamped[2], clamped[1]}
+	case 4:
+		return edges{clamped[0], clamped[1], clamped[2], clamped[3]}
+	}
+	return edges{}
+}
+
+func (e edges) horizontal() int {
+	return e.left + e.right
+}
+
+func (e edges) vertical() int {
+	return e.top + e.bottom
+}
+
+// insetable – padding and margin of view. Embedded into views, padding is
+// space between content and edges of view (filled with view background and
+// reacting to view gesture), margin is empty space around view.
+type insetable struct {
+	padding, margin edges
+}
+
+func (i *insetable) insets() (edges, edges) {
+	return i.margin, i.padding
+}
+
+// insetView – implemented by views with padding and margin
+type insetView interface {
+	insets() (margin, padding edges)
+}
+
+// insetsOf returns margin and padding of view, padding set by stylesheet
+// overrides own padding of view
+func insetsOf(v View) (edges, edges) {
+	var margin, padding edges
+	if asserted, ok := v.(insetView); ok {
+		margin, padding = asserted.insets()
+	}
+	if asserted, ok := v.(stylePaddedView); ok {
+		if stylePadding, ok := asserted.stylePadding(); ok {
+			padding = stylePadding
+		}
+	}
+	return margin, padding
+}
+
+// backgroundView – implemented by views that have background, padding of
+// such views is filled with it
+type backgroundView interface {
+	backgroundColor() proto.Color
+}
+
+// hostedView – implemented by views that need to know the window they are
+// shown in (e.g. to redraw it by timer)
+type hostedView interface {
+	setHost(window *_Window)
+}
+
+// windowHost – embedded by containers to redraw the window they are shown in
+// when their children change
+type windowHost struct {
+	host *_Window
+}
+
+// setHost implements hostedView.
+func (h *windowHost) setHost(window *_Window) {
+	h.host = window
+}
+
+// invalidate redraws the window, so layout and gesture areas are updated
+func (h *windowHost) invalidate() {
+	if h.host != nil {
+		h.host.invalidate()
+	}
+}
+
+// indexOfView returns index of view in views or -1
+func indexOfView(views []View, view View) int {
+	for i, v := range views {
+		if v == view {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertView returns views with view inserted at index, index out of range
+// adds view to the end
+func insertView(views []View, index int, view View) []View {
+	if index < 0 || index > len(views) {
+		index = len(views)
+	}
+	views = append(views, nil)
+	copy(views[index+1:], views[index:])
+	views[index] = view
+	return views
+}
+
+// removeViewAt returns views without view at index
+func removeViewAt(views []View, index int) []View {
+	if index < 0 || index >= len(views) {
+		return views
+	}
+	return append(views[:index], views[index+1:]...)
 }
 
 func max(x, y int) int {
@@ -58,6 +225,17 @@ func pointInArea(x, y int, area GestureDescriptor) bool {
 	return false
 }
 
+// clipArea returns part of gesture area inside clip area and reports
+// whether it is not empty
+func clipArea(area, clip GestureDescriptor) (GestureDescriptor, bool) {
+	left, top := max(area.x, clip.x), max(area.y, clip.y)
+	right := min(area.x+area.width, clip.x+clip.width)
+	bottom := min(area.y+area.height, clip.y+clip.height)
+	area.x, area.y = left, top
+	area.width, area.height = right-left, bottom-top
+	return area, area.width > 0 && area.height > 0
+}
+
 // Some popular colors
 
 var White = proto.Color{A: 255, R: 255, G: 255, B: 255}
@@ -0,0 +1,405 @@
+package fwsui
+
+import (
+	proto "github.com/Nekhaevalex/fwsprotocol"
+	"github.com/nsf/termbox-go"
+)
+
+// navigationPage – view pushed to navigation stack
+type navigationPage struct {
+	title   string
+	view    View
+	focused View // focus inside page saved when page is covered by another
+}
+
+// _NavigationStack – container showing the last pushed view under optional
+// header with its title and back button. Views under it keep their state
+// and are shown again when views above them are popped.
+type _NavigationStack struct {
+	x, y, width, height int
+	awidth, aheight     int
+	pages               []*navigationPage
+	header              bool
+	back                *_Button
+	onNavigate          func(top View)
+	theme               *Theme
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
+}
+
+// getGesture implements View.
+func (*_NavigationStack) getGesture() Gesture {
+	return nil
+}
+
+// hasGesture implements View.
+func (*_NavigationStack) hasGesture() bool {
+	return false
+}
+
+func (stack *_NavigationStack) getLogicalSize() (int, int) {
+	return stack.width, stack.height
+}
+
+func (stack *_NavigationStack) getActualSize() (int, int) {
+	return stack.awidth, stack.aheight
+}
+
+func (stack *_NavigationStack) setPos(x, y int) {
+	stack.x = x
+	stack.y = y
+}
+
+func (stack *_NavigationStack) getPos() (int, int) {
+	return stack.x, stack.y
+}
+
+// setTheme implements themedView.
+func (stack *_NavigationStack) setTheme(theme *Theme) {
+	stack.theme = theme
+}
+
+// top returns page on top of stack
+func (stack *_NavigationStack) top() *navigationPage {
+	if len(stack.pages) == 0 {
+		return nil
+	}
+	return stack.pages[len(stack.pages)-1]
+}
+
+// showsBack reports whether back button is shown in header
+func (stack *_NavigationStack) showsBack() bool {
+	return stack.header && len(stack.pages) > 1
+}
+
+// headerHeight returns amount of rows taken by header
+func (stack *_NavigationStack) headerHeight() int {
+	if stack.header {
+		return 1
+	}
+	return 0
+}
+
+// containsView reports whether view is placed in page
+func containsView(page View, view View) bool {
+	found := false
+	walkViews(page, func(v View) {
+		found = found || v == view
+	})
+	return found
+}
+
+// firstFocusableView returns the first enabled view of page that can get focus
+func firstFocusableView(page View) View {
+	var first View
+	walkEnabledViews(page, true, func(v View, enabled bool) {
+		if asserted, ok := v.(KeyHandler); ok && first == nil && enabled && asserted.canFocus() {
+			first = v
+		}
+	})
+	return first
+}
+
+// transition updates back button and passes focus from page that was on top
+// to the page on top now: focus of covered page is saved, focus of page shown
+// again is restored, new page focuses its first focusable view. Focus
+// outside of stack isn't moved. Pointer state of hidden page is dropped and
+// window is redrawn, so gesture areas follow the new page.
+func (stack *_NavigationStack) transition(previous *navigationPage) {
+	if len(stack.pages) > 1 {
+		title := stack.pages[len(stack.pages)-2].title
+		if title == "" {
+			title = "Back"
+		}
+		label := "‹ " + title
+		stack.back.SetText(label).SetSize(stringWidth(label)+2, 1)
+	}
+	window := stack.host
+	if window == nil {
+		return
+	}
+	window.hoverGesture, window.pressedGesture = nil, nil
+	top := stack.top()
+	focused := window.focused
+	inside := focused == nil || focused == stack || focused == stack.back
+	if previous != nil && focused != nil && containsView(previous.view, focused) {
+		inside = true
+		previous.focused = focused
+	}
+	if inside && top != nil {
+		target := top.focused
+		if target == nil || !containsView(top.view, target) {
+			target = firstFocusableView(top.view)
+		}
+		switch {
+		case target != nil:
+		case stack.showsBack():
+			target = stack.back
+		case stack.canFocus():
+			target = stack
+		}
+		window.setFocus(target)
+	}
+	stack.invalidate()
+	if stack.onNavigate != nil && top != nil {
+		stack.onNavigate(top.view)
+	}
+}
+
+func (stack *_NavigationStack) render(width, height int) [][]proto.Cell {
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := stack.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
+	stack.awidth = width
+	stack.aheight = height
+	canvas := allocateCanvas(width, height)
+	top := stack.top()
+	if top == nil || height == 0 {
+		return canvas
+	}
+	if stack.header {
+		theme := themeOrDefault(stack.theme)
+		fg, bg := stack.style.colors(theme.Text, theme.Surface, stack.theme)
+		for x := 0; x < width; x++ {
+			canvas[x][0] = proto.Cell{Ch: ' ', Fg: fg, Bg: bg}
+		}
+		backWidth := 0
+		if stack.showsBack() {
+			measured, _ := measureView(stack.back, -1, 1)
+			backWidth = min(width, measured.ideal)
+			placeView(canvas, stack.back, 0, 0, backWidth, 1)
+		}
+		// Title is centered unless it would cover back button
+		titleX, titleAlign := 0, Center
+		if (width-stringWidth(top.title))/2 <= backWidth {
+			titleX, titleAlign = backWidth+1, Left
+		}
+		drawAligned(canvas, titleX, 0, width-titleX, top.title, titleAlign, fg, bg, proto.Attr(termbox.AttrBold))
+	}
+	headerHeight := stack.headerHeight()
+	placeView(canvas, top.view, 0, headerHeight, width, height-headerHeight)
+	fixWideCells(canvas)
+	return canvas
+}
+
+// measure implements measurer. Stack ideally fits header and the view on
+// top.
+func (stack *_NavigationStack) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	headerHeight := stack.headerHeight()
+	width, height := flexibleSize(0, 0), flexibleSize(headerHeight, headerHeight)
+	if top := stack.top(); top != nil {
+		w, h := measureView(top.view, proposedWidth, max(-1, proposedHeight-headerHeight))
+		width = flexibleSize(w.min, w.ideal)
+		height = flexibleSize(h.min+headerHeight, h.ideal+headerHeight)
+	}
+	if stack.width >= 0 {
+		width = fixedSize(stack.width)
+	}
+	if stack.height >= 0 {
+		height = fixedSize(stack.height)
+	}
+	return width, height
+}
+
+// subviews returns back button (if shown) and view on top of stack
+func (stack *_NavigationStack) subviews() []View {
+	views := make([]View, 0, 2)
+	if stack.showsBack() {
+		views = append(views, stack.back)
+	}
+	if top := stack.top(); top != nil {
+		views = append(views, top.view)
+	}
+	return views
+}
+
+// getChildrenGestures returns gestures of back button and view on top of
+// stack
+func (stack *_NavigationStack) getChildrenGestures(x, y int) []GestureDescriptor {
+	actors := make([]GestureDescriptor, 0)
+	for _, child := range stack.subviews() {
+		if !viewEnabled(child) {
+			continue
+		}
+		if child.hasGesture() {
+			actors = append(actors, child.getGesture().getGestureDescriptor(x+stack.x, y+stack.y))
+		}
+		if asserted, ok := child.(Container); ok {
+			actors = append(actors, asserted.getChildrenGestures(x+stack.x, y+stack.y)...)
+		}
+	}
+	return actors
+}
+
+// canFocus implements KeyHandler. Stack gets focus only if there is neither
+// focusable view on top nor back button, so Esc still goes back.
+func (stack *_NavigationStack) canFocus() bool {
+	return !stack.disabled && len(stack.pages) > 1 && !stack.header && firstFocusableView(stack.top().view) == nil
+}
+
+// handleKey implements KeyHandler. Esc pops the view on top.
+func (stack *_NavigationStack) handleKey(event *proto.EventRequest) bool {
+	if event.Ch != 0 || event.Key != termbox.KeyEsc || len(stack.pages) < 2 {
+		return false
+	}
+	stack.Pop()
+	return true
+}
+
+// focusChanged implements KeyHandler.
+func (stack *_NavigationStack) focusChanged(focused bool) {}
+
+// Push shows view with title on top of stack
+func (stack *_NavigationStack) Push(title string, view View) *_NavigationStack {
+	previous := stack.top()
+	stack.pages = append(stack.pages, &navigationPage{title: title, view: viewOrEmpty(view)})
+	stack.transition(previous)
+	return stack
+}
+
+// Pop removes view on top of stack showing view under it again. The root
+// view is never popped.
+func (stack *_NavigationStack) Pop() *_NavigationStack {
+	if len(stack.pages) < 2 {
+		return stack
+	}
+	previous := stack.top()
+	stack.pages = stack.pages[:len(stack.pages)-1]
+	stack.transition(previous)
+	return stack
+}
+
+// PopToRoot removes all views except the root one
+func (stack *_NavigationStack) PopToRoot() *_NavigationStack {
+	if len(stack.pages) < 2 {
+		return stack
+	}
+	previous := stack.top()
+	stack.pages = stack.pages[:1]
+	stack.transition(previous)
+	return stack
+}
+
+// Top returns view on top of stack
+func (stack *_NavigationStack) Top() View {
+	if top := stack.top(); top != nil {
+		return top.view
+	}
+	return nil
+}
+
+// Depth returns amount of views in stack including the root one
+func (stack *_NavigationStack) Depth() int {
+	return len(stack.pages)
+}
+
+// Children returns views of stack from the root one to the top one
+func (stack *_NavigationStack) Children() []View {
+	views := make([]View, len(stack.pages))
+	for i, page := range stack.pages {
+		views[i] = page.view
+	}
+	return views
+}
+
+// Header sets whether header with title and back button is shown (true by
+// default)
+func (stack *_NavigationStack) Header(b bool) *_NavigationStack {
+	stack.header = b
+	return stack
+}
+
+// OnNavigate sets action called with view on top after push or pop
+func (stack *_NavigationStack) OnNavigate(action func(top View)) *_NavigationStack {
+	stack.onNavigate = action
+	return stack
+}
+
+func (stack *_NavigationStack) SetSize(width, height int) *_NavigationStack {
+	stack.width = width
+	stack.height = height
+	return stack
+}
+
+// Disabled disables (or enables) stack
+func (stack *_NavigationStack) Disabled(b bool) *_NavigationStack {
+	stack.disabled = b
+	return stack
+}
+
+// StyleClass sets stylesheet classes of stack
+func (stack *_NavigationStack) StyleClass(classes ...string) *_NavigationStack {
+	stack.classes = classes
+	return stack
+}
+
+func (stack *_NavigationStack) Padding(sides ...int) *_NavigationStack {
+	stack.padding = edgesOf(sides...)
+	return stack
+}
+
+func (stack *_NavigationStack) Margin(sides ...int) *_NavigationStack {
+	stack.margin = edgesOf(sides...)
+	return stack
+}
+
+func (stack *_NavigationStack) Flex(weight int) *_NavigationStack {
+	stack.setFlex(weight)
+	return stack
+}
+
+func (stack *_NavigationStack) LayoutPriority(priority int) *_NavigationStack {
+	stack.setPriority(priority)
+	return stack
+}
+
+func (stack *_NavigationStack) MinWidth(width int) *_NavigationStack {
+	stack.setWidthLimits(max(0, width), -1)
+	return stack
+}
+
+func (stack *_NavigationStack) MaxWidth(width int) *_NavigationStack {
+	stack.setWidthLimits(-1, max(0, width))
+	return stack
+}
+
+func (stack *_NavigationStack) MinHeight(height int) *_NavigationStack {
+	stack.setHeightLimits(max(0, height), -1)
+	return stack
+}
+
+func (stack *_NavigationStack) MaxHeight(height int) *_NavigationStack {
+	stack.setHeightLimits(-1, max(0, height))
+	return stack
+}
+
+// NavigationStack creates stack with root view, e.g. list drilling down to
+// details:
+//
+//	type userSource []User
+//
+//	func (users userSource) Count() int { return len(users) }
+//	func (users userSource) DrawRow(p *_Painter, index int, selected bool) {
+//		p.Text(0, 0, users[index].Name, p.Theme().Text)
+//	}
+//
+//	var stack *_NavigationStack
+//	stack = NavigationStack("Users", List(userSource(users)).OnActivate(func(index int) {
+//		stack.Push(users[index].Name, details(users[index]))
+//	}))
+func NavigationStack(title string, root View) *_NavigationStack {
+	stack := new(_NavigationStack)
+	stack.kind = "NavigationStack"
+	stack.width = -1
+	stack.height = -1
+	stack.header = true
+	stack.back = Button("‹ Back", func(*_Button) {
+		stack.Pop()
+	})
+	stack.pages = []*navigationPage{{title: title, view: viewOrEmpty(root)}}
+	return stack
+}
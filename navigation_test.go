@@ -0,0 +1,77 @@
+package fwsui
+
+import (
+	"testing"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestNavigationStackRestoresFocus(t *testing.T) {
+	window := new(_Window)
+	first := Button("First", func(*_Button) {})
+	second := Button("Second", func(*_Button) {})
+	stack := NavigationStack("Root", VStack(first, second))
+	stack.setHost(window)
+	window.setFocus(second)
+
+	name := ""
+	field := TextField(&name, "Name")
+	stack.Push("Details", VStack(Text("Name"), field))
+	if window.focused != field {
+		t.Errorf("pushed page focuses %T, want its text field", window.focused)
+	}
+	stack.Pop()
+	if window.focused != second {
+		t.Errorf("page shown again focuses %T, want button it had focused", window.focused)
+	}
+
+	// Focus saved in covered page survives several pages above it
+	stack.Push("Details", VStack(field)).Push("Info", Text("info"))
+	if window.focused != stack.back {
+		t.Errorf("page without focusable views focuses %T, want back button", window.focused)
+	}
+	stack.Pop()
+	if window.focused != field {
+		t.Errorf("page shown again focuses %T, want its text field", window.focused)
+	}
+	stack.PopToRoot()
+	if window.focused != second || stack.Depth() != 1 {
+		t.Errorf("root page focuses %T at depth %d, want its button at 1", window.focused, stack.Depth())
+	}
+}
+
+func TestNavigationStackKeepsOutsideFocus(t *testing.T) {
+	window := new(_Window)
+	outside := Button("Outside", func(*_Button) {})
+	stack := NavigationStack("Root", Button("Inside", func(*_Button) {}))
+	stack.setHost(window)
+	window.setFocus(outside)
+	stack.Push("Details", Button("Details", func(*_Button) {}))
+	stack.Pop()
+	if window.focused != outside {
+		t.Errorf("focus outside of stack is moved to %T", window.focused)
+	}
+}
+
+func TestNavigationStackEsc(t *testing.T) {
+	window := new(_Window)
+	depths := []int{}
+	stack := NavigationStack("Root", Text("root")).Header(false)
+	stack.OnNavigate(func(top View) {
+		depths = append(depths, stack.Depth())
+	})
+	stack.setHost(window)
+	stack.Push("Info", Text("info"))
+	if window.focused != stack {
+		t.Errorf("page without focusable views and header focuses %T, want stack", window.focused)
+	}
+	if !stack.handleKey(keyEvent(termbox.KeyEsc, 0)) || stack.Depth() != 1 {
+		t.Errorf("Esc doesn't pop, depth %d", stack.Depth())
+	}
+	if stack.handleKey(keyEvent(termbox.KeyEsc, 0)) || stack.Depth() != 1 {
+		t.Error("Esc pops root view")
+	}
+	if len(depths) != 2 || depths[0] != 2 || depths[1] != 1 {
+		t.Errorf("navigation is reported at depths %v, want [2 1]", depths)
+	}
+}
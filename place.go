@@ -0,0 +1,346 @@
+package fwsui
+
+import proto "github.com/Nekhaevalex/fwsprotocol"
+
+// _Placement – view placed in AbsoluteLayout with its frame. Offsets are
+// measured from edges of layout view is anchored to, pinned edges of view
+// keep their distance to edges of layout.
+type _Placement struct {
+	view                     View
+	x, y                     int
+	width, height            int // negative – ideal size of view
+	anchorX, anchorY         Align
+	top, right, bottom, left int // pins, negative – edge isn't pinned
+}
+
+// Place creates placement of view at the top left corner of layout
+func Place(view View) *_Placement {
+	placement := new(_Placement)
+	placement.view = viewOrEmpty(view)
+	placement.width = -1
+	placement.height = -1
+	placement.anchorX = Left
+	placement.anchorY = Left
+	placement.top, placement.right, placement.bottom, placement.left = -1, -1, -1, -1
+	return placement
+}
+
+// At sets offset of view from edges it is anchored to
+func (placement *_Placement) At(x, y int) *_Placement {
+	placement.x = x
+	placement.y = y
+	return placement
+}
+
+// Size sets size of view frame, negative size is ideal size of view
+func (placement *_Placement) Size(width, height int) *_Placement {
+	placement.width = width
+	placement.height = height
+	return placement
+}
+
+// Anchor sets edges of layout offsets are measured from: Left (top), Right
+// (bottom) or Center, e.g. Anchor(Right, Left).At(1, 0) places badge in the
+// top right corner one cell from the right edge
+func (placement *_Placement) Anchor(x, y Align) *_Placement {
+	placement.anchorX = x
+	placement.anchorY = y
+	return placement
+}
+
+// Pin keeps edges of view at distance from edges of layout (negative
+// distance – edge isn't pinned). View pinned to opposite edges is stretched.
+// Pins override offsets and anchors.
+func (placement *_Placement) Pin(top, right, bottom, left int) *_Placement {
+	placement.top, placement.right, placement.bottom, placement.left = top, right, bottom, left
+	return placement
+}
+
+// placeAxis returns position and size of view along axis of layout
+func placeAxis(space, offset, size int, anchor Align, pinStart, pinEnd int) (int, int) {
+	switch {
+	case pinStart >= 0 && pinEnd >= 0:
+		return pinStart, max(0, space-pinStart-pinEnd)
+	case pinStart >= 0:
+		return pinStart, size
+	case pinEnd >= 0:
+		return space - pinEnd - size, size
+	}
+	switch anchor {
+	case Center:
+		return space/2 - size/2 + offset, size
+	case Right:
+		return space - size - offset, size
+	}
+	return offset, size
+}
+
+// frame returns frame of view in layout of given size. Explicit size is
+// used as is, otherwise view takes its ideal size.
+func (placement *_Placement) frame(width, height int) (int, int, int, int) {
+	w, h := placement.width, placement.height
+	if placement.left >= 0 && placement.right >= 0 {
+		w = max(0, width-placement.left-placement.right)
+	}
+	if w < 0 {
+		widthRange, _ := measureView(placement.view, -1, h)
+		w = widthRange.ideal
+	}
+	if h < 0 {
+		_, heightRange := measureView(placement.view, w, -1)
+		h = heightRange.ideal
+	}
+	x, w := placeAxis(width, placement.x, w, placement.anchorX, placement.left, placement.right)
+	y, h := placeAxis(height, placement.y, h, placement.anchorY, placement.top, placement.bottom)
+	return x, y, w, h
+}
+
+// _AbsoluteLayout – container placing views at explicit positions. Views
+// are drawn in order they are added, later views are drawn over earlier ones
+// and get pointer events first.
+type _AbsoluteLayout struct {
+	x, y, width, height int
+	awidth, aheight     int
+	placements          []*_Placement
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
+}
+
+// getGesture implements View.
+func (*_AbsoluteLayout) getGesture() Gesture {
+	return nil
+}
+
+// hasGesture implements View.
+func (*_AbsoluteLayout) hasGesture() bool {
+	return false
+}
+
+func (layout *_AbsoluteLayout) getLogicalSize() (int, int) {
+	return layout.width, layout.height
+}
+
+func (layout *_AbsoluteLayout) getActualSize() (int, int) {
+	return layout.awidth, layout.aheight
+}
+
+func (layout *_AbsoluteLayout) setPos(x, y int) {
+	layout.x = x
+	layout.y = y
+}
+
+func (layout *_AbsoluteLayout) getPos() (int, int) {
+	return layout.x, layout.y
+}
+
+func (layout *_AbsoluteLayout) render(width, height int) [][]proto.Cell {
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := layout.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
+	layout.awidth = width
+	layout.aheight = height
+	canvas := allocateCanvas(width, height)
+	for _, placement := range layout.placements {
+		x, y, w, h := placement.frame(width, height)
+		layer := allocateCanvas(width, height)
+		placeView(layer, placement.view, x, y, w, h)
+		for i := max(0, x); i < min(width, x+w); i++ {
+			for j := max(0, y); j < min(height, y+h); j++ {
+				canvas[i][j] = layer[i][j].Over(canvas[i][j])
+			}
+		}
+	}
+	fixWideCells(canvas)
+	return canvas
+}
+
+// measure implements measurer. Layout ideally fits views placed from its
+// top left corner and views anchored to other edges with their offsets;
+// floating layout can grow.
+func (layout *_AbsoluteLayout) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	idealWidth, idealHeight := 0, 0
+	for _, placement := range layout.placements {
+		_, _, w, h := placement.frame(0, 0)
+		idealWidth = max(idealWidth, max(0, placement.left)+max(0, placement.right)+abs(placement.x)+w)
+		idealHeight = max(idealHeight, max(0, placement.top)+max(0, placement.bottom)+abs(placement.y)+h)
+	}
+	width, height := flexibleSize(0, idealWidth), flexibleSize(0, idealHeight)
+	if layout.width >= 0 {
+		width = fixedSize(layout.width)
+	}
+	if layout.height >= 0 {
+		height = fixedSize(layout.height)
+	}
+	return width, height
+}
+
+func (layout *_AbsoluteLayout) subviews() []View {
+	views := make([]View, len(layout.placements))
+	for i, placement := range layout.placements {
+		views[i] = placement.view
+	}
+	return views
+}
+
+func (layout *_AbsoluteLayout) getChildrenGestures(x, y int) []GestureDescriptor {
+	actors := make([]GestureDescriptor, 0)
+	for _, placement := range layout.placements {
+		child := placement.view
+		if !viewEnabled(child) {
+			continue
+		}
+		if child.hasGesture() {
+			actors = append(actors, child.getGesture().getGestureDescriptor(x+layout.x, y+layout.y))
+		}
+		if asserted, ok := child.(Container); ok {
+			actors = append(actors, asserted.getChildrenGestures(x+layout.x, y+layout.y)...)
+		}
+	}
+	return actors
+}
+
+// Add adds placed view over views of layout
+func (layout *_AbsoluteLayout) Add(placement *_Placement) *_AbsoluteLayout {
+	layout.placements = append(layout.placements, placement)
+	layout.invalidate()
+	return layout
+}
+
+// AddView adds view at position from the top left corner of layout
+func (layout *_AbsoluteLayout) AddView(view View, x, y int) *_AbsoluteLayout {
+	return layout.Add(Place(view).At(x, y))
+}
+
+// Insert inserts placed view at index of drawing order, index out of range
+// adds it over all views
+func (layout *_AbsoluteLayout) Insert(index int, placement *_Placement) *_AbsoluteLayout {
+	if index < 0 || index > len(layout.placements) {
+		index = len(layout.placements)
+	}
+	layout.placements = append(layout.placements, nil)
+	copy(layout.placements[index+1:], layout.placements[index:])
+	layout.placements[index] = placement
+	layout.invalidate()
+	return layout
+}
+
+// InsertView inserts view placed at the top left corner at index of drawing
+// order
+func (layout *_AbsoluteLayout) InsertView(index int, view View) *_AbsoluteLayout {
+	return layout.Insert(index, Place(view))
+}
+
+// RemoveView removes view from layout
+func (layout *_AbsoluteLayout) RemoveView(view View) *_AbsoluteLayout {
+	return layout.RemoveAt(indexOfView(layout.subviews(), view))
+}
+
+// RemoveAt removes view at index of drawing order
+func (layout *_AbsoluteLayout) RemoveAt(index int) *_AbsoluteLayout {
+	if index < 0 || index >= len(layout.placements) {
+		return layout
+	}
+	layout.placements = append(layout.placements[:index], layout.placements[index+1:]...)
+	layout.invalidate()
+	return layout
+}
+
+// ReplaceView puts view in place of old one keeping its placement
+func (layout *_AbsoluteLayout) ReplaceView(old, view View) *_AbsoluteLayout {
+	if index := indexOfView(layout.subviews(), old); index >= 0 {
+		layout.placements[index].view = viewOrEmpty(view)
+		layout.invalidate()
+	}
+	return layout
+}
+
+// Clear removes all views from layout
+func (layout *_AbsoluteLayout) Clear() *_AbsoluteLayout {
+	layout.placements = nil
+	layout.invalidate()
+	return layout
+}
+
+// Children returns views of layout in drawing order
+func (layout *_AbsoluteLayout) Children() []View {
+	return layout.subviews()
+}
+
+func (layout *_AbsoluteLayout) SetSize(width, height int) *_AbsoluteLayout {
+	layout.width = width
+	layout.height = height
+	return layout
+}
+
+// Disabled disables (or enables) all views of layout: they don't react to
+// input and are drawn with disabled style
+func (layout *_AbsoluteLayout) Disabled(b bool) *_AbsoluteLayout {
+	layout.disabled = b
+	return layout
+}
+
+// StyleClass sets stylesheet classes of layout
+func (layout *_AbsoluteLayout) StyleClass(classes ...string) *_AbsoluteLayout {
+	layout.classes = classes
+	return layout
+}
+
+func (layout *_AbsoluteLayout) Padding(sides ...int) *_AbsoluteLayout {
+	layout.padding = edgesOf(sides...)
+	return layout
+}
+
+func (layout *_AbsoluteLayout) Margin(sides ...int) *_AbsoluteLayout {
+	layout.margin = edgesOf(sides...)
+	return layout
+}
+
+func (layout *_AbsoluteLayout) Flex(weight int) *_AbsoluteLayout {
+	layout.setFlex(weight)
+	return layout
+}
+
+func (layout *_AbsoluteLayout) LayoutPriority(priority int) *_AbsoluteLayout {
+	layout.setPriority(priority)
+	return layout
+}
+
+func (layout *_AbsoluteLayout) MinWidth(width int) *_AbsoluteLayout {
+	layout.setWidthLimits(max(0, width), -1)
+	return layout
+}
+
+func (layout *_AbsoluteLayout) MaxWidth(width int) *_AbsoluteLayout {
+	layout.setWidthLimits(-1, max(0, width))
+	return layout
+}
+
+func (layout *_AbsoluteLayout) MinHeight(height int) *_AbsoluteLayout {
+	layout.setHeightLimits(max(0, height), -1)
+	return layout
+}
+
+func (layout *_AbsoluteLayout) MaxHeight(height int) *_AbsoluteLayout {
+	layout.setHeightLimits(-1, max(0, height))
+	return layout
+}
+
+// AbsoluteLayout creates container with views placed at explicit positions,
+// e.g. icon with badge in its corner:
+//
+//	AbsoluteLayout(
+//		Place(icon).Size(6, 3),
+//		Place(Text("3").Background(Red)).Anchor(Right, Left),
+//	).SetSize(6, 3)
+func AbsoluteLayout(placements ...*_Placement) *_AbsoluteLayout {
+	layout := new(_AbsoluteLayout)
+	layout.placements = placements
+	layout.kind = "AbsoluteLayout"
+	layout.width = -1
+	layout.height = -1
+	return layout
+}
@@ -13,6 +13,7 @@ type Scene interface {
 	getEventChannel() chan *proto.EventRequest // Method for returning events incomming connection
 	buildContent()                             // Method for building contained views
 	eventHandler()                             // Handler for incomming events
+	invalidate()                               // Method for requesting redraw
 }
 
 type _Window struct {
@@ -23,6 +24,7 @@ type _Window struct {
 	title               string
 	activeAreas         []GestureDescriptor
 	events              chan *proto.EventRequest
+	redrawRequests      chan int
 	quit                chan int
 	background          proto.Color
 	body                View
@@ -33,10 +35,18 @@ type _Window struct {
 	lastW, lastH        int
 	onCloseFunc         func()
 	titleText           *_Text
+	theme               *Theme              // window theme, app theme is used if nil
+	stylesheet          *_Stylesheet        // window stylesheet, app stylesheet is used if nil
+	chrome              []View              // title bar, buttons and other styled parts of window frame
+	hoverGesture        Gesture             // gesture under pointer
+	pressedGesture      Gesture             // gesture held by mouse button with pointer inside it
+	focused             View                // view receiving key events
+	hosted              map[hostedView]bool // views that got window pointer on the last attach
 }
 
 func (window *_Window) Close() {
-	delete(window.app.scenes, window.layerId)
+	window.detachViews(nil)
+	window.app.removeScene(window.layerId)
 	delete_request := &proto.DeleteRequest{Id: window.layerId}
 	window.app.sendRequest(delete_request)
 	window.onCloseFunc()
@@ -62,6 +72,45 @@ func (window *_Window) SetTitle(s string) *_Window {
 	return window
 }
 
+// SetTheme sets theme of window overriding app theme. Nil theme makes window
+// follow app theme again.
+func (window *_Window) SetTheme(theme *Theme) *_Window {
+	window.theme = theme
+	window.invalidate()
+	return window
+}
+
+// currentTheme returns theme window content is styled with
+func (window *_Window) currentTheme() *Theme {
+	if window.theme != nil {
+		return window.theme
+	}
+	if window.app != nil {
+		return window.app.Theme()
+	}
+	return LightTheme
+}
+
+// SetStylesheet sets stylesheet of window overriding app stylesheet. Nil
+// stylesheet makes window follow app stylesheet again.
+func (window *_Window) SetStylesheet(sheet *_Stylesheet) *_Window {
+	window.stylesheet = sheet
+	window.invalidate()
+	return window
+}
+
+// currentStylesheet returns stylesheet window content is styled with (can be
+// nil)
+func (window *_Window) currentStylesheet() *_Stylesheet {
+	if window.stylesheet != nil {
+		return window.stylesheet
+	}
+	if window.app != nil {
+		return window.app.currentStylesheet()
+	}
+	return nil
+}
+
 func (window *_Window) bindApp(app *_App) {
 	window.app = app
 }
@@ -124,9 +173,7 @@ func (window *_Window) buildContent() {
 		window.lastY = 0
 	})
 
-	shadowColor := Black
-	shadowColor.A = 127
-	shadowRect := Text("").SetSize(-1, -1).Background(shadowColor).Foreground(shadowColor)
+	shadowRect := Text("").SetSize(-1, -1).BackgroundRole(ShadowRole).ForegroundRole(ShadowRole).styleKind("Shadow")
 
 	shadowLayer := VStack(
 		Spacer().SetSize(-1, 1),
@@ -142,25 +189,32 @@ func (window *_Window) buildContent() {
 		window.lastH = 0
 	})
 
-	window.titleText = Text(window.title).Foreground(White).Background(Grey).Align(Center).SetSize(-1, -1).Gesture(windowMoveGesture)
+	window.titleText = Text(window.title).ForegroundRole(TitleTextRole).BackgroundRole(TitleBarRole).Align(Center).SetSize(-1, -1).Gesture(windowMoveGesture).styleKind("TitleBar")
+
+	closeButton := Button("X", func(outlet *_Button) {
+		window.Close()
+	}).ForegroundRole(CloseButtonTextRole).BackgroundRole(CloseButtonRole).styleKind("CloseButton")
+	minimizeButton := Button("-", func(outlet *_Button) {
+		// Todo
+	}).ForegroundRole(MinimizeButtonTextRole).BackgroundRole(MinimizeButtonRole).styleKind("MinimizeButton")
+	maximizeButton := Button("+", func(outlet *_Button) {
+		// Todo
+	}).ForegroundRole(MaximizeButtonTextRole).BackgroundRole(MaximizeButtonRole).styleKind("MaximizeButton")
+	bodyBackground := Text("").BackgroundRole(BackgroundRole).ForegroundRole(BackgroundRole).SetSize(-1, -1).styleKind("Window")
+	resizeHandle := Text("⇲").BackgroundRole(BackgroundRole).ForegroundRole(TextRole).Gesture(resizeGesture).styleKind("ResizeHandle")
+	window.chrome = []View{shadowRect, window.titleText, closeButton, minimizeButton, maximizeButton, bodyBackground, resizeHandle}
 
 	windowFrame := VStack(
 		HStack(
-			Button("X", func(outlet *_Button) {
-				window.Close()
-			}).Foreground(White).Background(Red),
-			Button("-", func(outlet *_Button) {
-				// Todo
-			}).Foreground(Grey).Background(Yellow),
-			Button("+", func(outlet *_Button) {
-				// Todo
-			}).Foreground(White).Background(Green),
+			closeButton,
+			minimizeButton,
+			maximizeButton,
 			window.titleText,
 		).SetSize(-1, 1),
 		ZStack(
-			Text("").Background(White).Foreground(White).SetSize(-1, -1),
+			bodyBackground,
 			window.body,
-			Box(Text("⇲").Background(White).Foreground(Black).Gesture(resizeGesture)).Gravity(Right, Right).SetSize(-1, -1),
+			Box(resizeHandle).Gravity(Right, Right).SetSize(-1, -1),
 		))
 
 	realLayer := VStack(
@@ -176,7 +230,192 @@ func (window *_Window) buildContent() {
 	window.redraw()
 }
 
+// invalidate asks window to redraw its content. Can be called from any
+// goroutine, redraw happens in window event loop.
+func (window *_Window) invalidate() {
+	select {
+	case window.redrawRequests <- 1:
+	default:
+	}
+}
+
+// detachViews takes window pointer from views that aren't in window anymore,
+// so they stop background work like animations. Views of hosted set are
+// remembered as attached ones, nil set detaches all views.
+func (window *_Window) detachViews(hosted map[hostedView]bool) {
+	for v := range window.hosted {
+		if !hosted[v] {
+			v.setHost(nil)
+		}
+	}
+	window.hosted = hosted
+}
+
+// attachViews passes window pointer, theme and stylesheet styles to views
+// that need them. Layout stacks of window frame are not styled, so stack
+// styles don't break it. Focus is removed from views that are disabled or
+// not shown anymore.
+func (window *_Window) attachViews() {
+	theme := window.currentTheme()
+	hosted := make(map[hostedView]bool)
+	walkViews(window.windowContainer, func(v View) {
+		if asserted, ok := v.(hostedView); ok {
+			asserted.setHost(window)
+			hosted[asserted] = true
+		}
+		if asserted, ok := v.(themedView); ok {
+			asserted.setTheme(theme)
+		}
+	})
+	window.detachViews(hosted)
+	sheet := window.currentStylesheet()
+	focusShown := false
+	applyStyle := func(v View, enabled bool) {
+		if asserted, ok := v.(styledView); ok {
+			asserted.applyStyle(sheet.styleFor(asserted.styleSelector()))
+		}
+		if asserted, ok := v.(statefulView); ok {
+			asserted.setInteractionState(window.stateOf(v, enabled))
+		}
+		if v == window.focused {
+			focusShown = enabled
+		}
+	}
+	walkEnabledViews(window.body, true, applyStyle)
+	if !focusShown {
+		window.setFocus(nil)
+	}
+	for _, v := range window.chrome {
+		applyStyle(v, true)
+	}
+}
+
+// stateOf returns interaction state of view
+func (window *_Window) stateOf(v View, enabled bool) InteractionState {
+	if !enabled {
+		return DisabledState
+	}
+	if v.hasGesture() {
+		gesture := v.getGesture()
+		if gesture == window.pressedGesture {
+			return PressedState
+		}
+		if gesture == window.hoverGesture && window.pressedGesture == nil {
+			return HoverState
+		}
+	}
+	if v == window.focused {
+		return FocusedState
+	}
+	return NormalState
+}
+
+// trackPointer updates hovered and pressed gestures and reports whether
+// they have changed
+func (window *_Window) trackPointer(event *proto.EventRequest, actor Gesture) bool {
+	hover := window.getGestureInPoint(event.MouseX, event.MouseY)
+	var pressed Gesture
+	switch event.Key {
+	case termbox.MouseLeft, termbox.MouseMiddle, termbox.MouseRight:
+		if actor != nil && actor == hover {
+			pressed = actor
+		}
+	}
+	changed := hover != window.hoverGesture || pressed != window.pressedGesture
+	window.hoverGesture = hover
+	window.pressedGesture = pressed
+	return changed
+}
+
+// focusableViews returns enabled views of window body that can get focus in
+// tree order
+func (window *_Window) focusableViews() []View {
+	views := make([]View, 0)
+	walkEnabledViews(window.body, true, func(v View, enabled bool) {
+		if asserted, ok := v.(KeyHandler); ok && enabled && asserted.canFocus() {
+			views = append(views, v)
+		}
+	})
+	return views
+}
+
+// setFocus moves keyboard focus to view (nil removes focus)
+func (window *_Window) setFocus(v View) {
+	if v == window.focused {
+		return
+	}
+	previous := window.focused
+	window.focused = v
+	if asserted, ok := previous.(KeyHandler); ok {
+		asserted.focusChanged(false)
+	}
+	if asserted, ok := v.(KeyHandler); ok {
+		asserted.focusChanged(true)
+	}
+}
+
+// focusNext moves focus to the next focusable view
+func (window *_Window) focusNext() {
+	views := window.focusableViews()
+	if len(views) == 0 {
+		window.setFocus(nil)
+		return
+	}
+	next := 0
+	for i, v := range views {
+		if v == window.focused {
+			next = (i + 1) % len(views)
+		}
+	}
+	window.setFocus(views[next])
+}
+
+// Focus moves keyboard focus to view shown in window
+func (window *_Window) Focus(v View) *_Window {
+	window.setFocus(v)
+	window.invalidate()
+	return window
+}
+
+// focusPath returns focused view and containers it is placed in, innermost
+// first
+func (window *_Window) focusPath() []View {
+	if window.focused == nil {
+		return nil
+	}
+	var find func(v View) []View
+	find = func(v View) []View {
+		if v == window.focused {
+			return []View{v}
+		}
+		if asserted, ok := v.(Container); ok {
+			for _, child := range asserted.subviews() {
+				if path := find(child); path != nil {
+					return append(path, v)
+				}
+			}
+		}
+		return nil
+	}
+	return find(window.body)
+}
+
+// handleKey passes key event to focused view and then to containers it is
+// placed in (e.g. scroll view pages with keys button doesn't use), Tab
+// switches focus if no view uses it
+func (window *_Window) handleKey(event *proto.EventRequest) {
+	for _, v := range window.focusPath() {
+		if asserted, ok := v.(KeyHandler); ok && asserted.handleKey(event) {
+			return
+		}
+	}
+	if event.Ch == 0 && event.Key == termbox.KeyTab {
+		window.focusNext()
+	}
+}
+
 func (window *_Window) redraw() {
+	window.attachViews()
 	window.staticCanvas = window.render(window.width, window.height)
 	draw_request := &proto.DrawFillRequest{
 		Id:     window.layerId,
@@ -191,9 +430,15 @@ func (window *_Window) redraw() {
 	window.activeAreas = append(window.activeAreas, window.windowContainer.getChildrenGestures(0, 0)...)
 }
 
+// getGestureInPoint returns the topmost gesture in point getting clicks and
+// drags. Wheel gestures without alternative gesture only scroll, so they are
+// skipped and views under scrollable area get clicks on its blank parts.
 func (window *_Window) getGestureInPoint(x, y int) Gesture {
 	for i := len(window.activeAreas) - 1; i >= 0; i-- {
 		area := window.activeAreas[i]
+		if wheel, ok := area.pointer.(*wheelGesture); ok && wheel.altGesture == nil {
+			continue
+		}
 		if pointInArea(x, y, area) {
 			return area.pointer
 		}
@@ -201,6 +446,17 @@ func (window *_Window) getGestureInPoint(x, y int) Gesture {
 	return nil
 }
 
+// getWheelGestureInPoint returns the topmost wheel gesture in point
+func (window *_Window) getWheelGestureInPoint(x, y int) Gesture {
+	for i := len(window.activeAreas) - 1; i >= 0; i-- {
+		area := window.activeAreas[i]
+		if _, ok := area.pointer.(*wheelGesture); ok && pointInArea(x, y, area) {
+			return area.pointer
+		}
+	}
+	return nil
+}
+
 func (window *_Window) eventHandler() {
 	window.activeAreas = append(window.activeAreas, window.windowContainer.getChildrenGestures(0, 0)...)
 	for {
@@ -212,23 +468,28 @@ func (window *_Window) eventHandler() {
 				y := event.MouseY
 				//Experimental!!!
 				var actor Gesture
-				if !window.prevMouse.isSameObject(event) {
+				if event.Key == termbox.MouseWheelUp || event.Key == termbox.MouseWheelDown {
+					actor = window.getWheelGestureInPoint(x, y)
+				} else if !window.prevMouse.isSameObject(event) {
 					actor = window.getGestureInPoint(x, y)
 				} else {
 					actor = window.prevMouse.actor
 				}
 				window.prevMouse.save(event, actor)
+				stateChanged := window.trackPointer(event, actor)
 				// [Experimental]
 				if actor != nil {
 					actor.updating(event)
 					window.redraw()
+				} else if stateChanged {
+					window.redraw()
 				}
 			case termbox.EventKey:
-				if *window.app.keyInputChan != nil {
-					*window.app.keyInputChan <- event
-				}
+				window.handleKey(event)
 				window.redraw()
 			}
+		case <-window.redrawRequests:
+			window.redraw()
 		case <-window.quit:
 			return
 		}
@@ -274,6 +535,7 @@ func Window(title string, body View) *_Window {
 	window.title = title
 	window.body = body
 	window.events = make(chan *proto.EventRequest)
+	window.redrawRequests = make(chan int, 1)
 	window.quit = make(chan int)
 	window.background = proto.Color{A: 255, R: 255, G: 255, B: 255}
 	window.activeAreas = make([]GestureDescriptor, 0)
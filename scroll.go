@@ -0,0 +1,540 @@
+package fwsui
+
+import (
+	proto "github.com/Nekhaevalex/fwsprotocol"
+
+	"github.com/nsf/termbox-go"
+)
+
+// wheelGesture – gesture of scrollable area reacting to mouse wheel. Window
+// passes wheel events to the topmost wheel gesture under pointer, so gestures
+// of views inside scrollable area don't hide it. Other mouse events get to
+// wheel gesture only if it has alternative gesture handling them.
+type wheelGesture struct {
+	x, y, width, height int
+	descriptor          GestureDescriptor
+	delta               int
+	action              func(delta int)
+	altGesture          Gesture
+}
+
+func (wheel *wheelGesture) getGestureDescriptor(x, y int) GestureDescriptor {
+	descriptor := GestureDescriptor{
+		x:       wheel.x + x,
+		y:       wheel.y + y,
+		width:   wheel.width,
+		height:  wheel.height,
+		pointer: wheel,
+	}
+	wheel.descriptor = descriptor
+	return descriptor
+}
+
+func (wheel *wheelGesture) setParentViewSizes(v View) {
+	wheel.x, wheel.y, wheel.width, wheel.height = hitArea(v)
+}
+
+func (wheel *wheelGesture) updating(event *proto.EventRequest) {
+	switch event.Key {
+	case termbox.MouseWheelUp:
+		wheel.delta = -1
+		wheel.onChanged()
+	case termbox.MouseWheelDown:
+		wheel.delta = 1
+		wheel.onChanged()
+	default:
+		if wheel.altGesture != nil {
+			wheel.altGesture.updating(event)
+		}
+	}
+}
+
+func (wheel *wheelGesture) onChanged() {
+	if wheel.action != nil {
+		wheel.action(wheel.delta)
+	}
+}
+
+func (wheel *wheelGesture) onEnded() {}
+
+func (wheel *wheelGesture) setAltGesture(gesture Gesture) {
+	wheel.altGesture = gesture
+}
+
+// _ScrollBar – scroll bar of ScrollView. Thumb shows visible part of content,
+// it can be dragged and click on track moves thumb to pointer.
+type _ScrollBar struct {
+	x, y, awidth, aheight int
+	vertical              bool
+	offset                int // offset of viewport in content
+	content, viewport     int
+	thumbColor, bg        proto.Color
+	gesture               *_DragGesture
+	dragging              bool
+	startOffset           int
+	scroll                func(offset int)
+}
+
+func newScrollBar(vertical bool, scroll func(offset int)) *_ScrollBar {
+	bar := new(_ScrollBar)
+	bar.vertical = vertical
+	bar.scroll = scroll
+	bar.gesture = DragGesture().OnChanged(func(value Value) {
+		length, thumbPos, thumbSize := bar.thumb()
+		localX, localY := value.LocalLocation()
+		translationX, translationY := value.Translation()
+		pos, translation := localX, translationX
+		if bar.vertical {
+			pos, translation = localY, translationY
+		}
+		if !bar.dragging {
+			bar.dragging = true
+			bar.startOffset = bar.offset
+			if pos < thumbPos || pos >= thumbPos+thumbSize {
+				// Click on track centers thumb at pointer
+				bar.startOffset = bar.offsetAt(pos-thumbSize/2, length, thumbSize)
+			}
+		}
+		bar.scroll(bar.startOffset + translation*(bar.content-bar.viewport)/max(1, length-thumbSize))
+	}).OnEnded(func(value Value) {
+		bar.dragging = false
+	})
+	return bar
+}
+
+// thumb returns length of track, position and size of thumb
+func (bar *_ScrollBar) thumb() (int, int, int) {
+	length := bar.awidth
+	if bar.vertical {
+		length = bar.aheight
+	}
+	if bar.content <= bar.viewport {
+		return length, 0, length
+	}
+	size := max(1, min(length, length*bar.viewport/max(1, bar.content)))
+	pos := (length - size) * bar.offset / (bar.content - bar.viewport)
+	return length, pos, size
+}
+
+// offsetAt returns content offset for thumb at position
+func (bar *_ScrollBar) offsetAt(pos, length, size int) int {
+	return max(0, pos) * (bar.content - bar.viewport) / max(1, length-size)
+}
+
+func (bar *_ScrollBar) getLogicalSize() (int, int) {
+	if bar.vertical {
+		return 1, -1
+	}
+	return -1, 1
+}
+
+func (bar *_ScrollBar) getActualSize() (int, int) {
+	return bar.awidth, bar.aheight
+}
+
+func (bar *_ScrollBar) getPos() (int, int) {
+	return bar.x, bar.y
+}
+
+func (bar *_ScrollBar) setPos(x, y int) {
+	bar.x = x
+	bar.y = y
+}
+
+func (bar *_ScrollBar) hasGesture() bool {
+	return true
+}
+
+func (bar *_ScrollBar) getGesture() Gesture {
+	bar.gesture.setParentViewSizes(bar)
+	return bar.gesture
+}
+
+func (bar *_ScrollBar) render(width, height int) [][]proto.Cell {
+	bar.awidth = width
+	bar.aheight = height
+	canvas := allocateCanvas(width, height)
+	_, thumbPos, thumbSize := bar.thumb()
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			pos := x
+			if bar.vertical {
+				pos = y
+			}
+			if pos >= thumbPos && pos < thumbPos+thumbSize {
+				canvas[x][y] = proto.Cell{Ch: '█', Fg: bar.thumbColor, Bg: bar.bg}
+			} else {
+				canvas[x][y] = proto.Cell{Ch: '░', Fg: bar.thumbColor, Bg: bar.bg, Attribute: proto.Attr(termbox.AttrDim)}
+			}
+		}
+	}
+	return canvas
+}
+
+// _ScrollView – container that shows part of its child through viewport.
+// Child is rendered in its full measured size and moved by scroll offset.
+type _ScrollView struct {
+	x, y, width, height  int
+	awidth, aheight      int
+	child                View
+	vertical, horizontal bool // axes content can be scrolled along
+	offsetX, offsetY     int
+	contentW, contentH   int
+	viewW, viewH         int
+	vbar, hbar           *_ScrollBar
+	showV, showH         bool
+	wheel                *wheelGesture
+	focused              bool
+	theme                *Theme
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
+}
+
+// getGesture implements View.
+func (*_ScrollView) getGesture() Gesture {
+	return nil
+}
+
+// hasGesture implements View.
+func (*_ScrollView) hasGesture() bool {
+	return false
+}
+
+func (sv *_ScrollView) getLogicalSize() (int, int) {
+	return sv.width, sv.height
+}
+
+func (sv *_ScrollView) getActualSize() (int, int) {
+	return sv.awidth, sv.aheight
+}
+
+func (sv *_ScrollView) setPos(x, y int) {
+	sv.x = x
+	sv.y = y
+}
+
+func (sv *_ScrollView) getPos() (int, int) {
+	return sv.x, sv.y
+}
+
+// setTheme implements themedView.
+func (sv *_ScrollView) setTheme(theme *Theme) {
+	sv.theme = theme
+}
+
+// measure implements measurer. Scroll view ideally takes size of its child,
+// but can be shrunk to nothing along axes it scrolls.
+func (sv *_ScrollView) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	width, height := measureView(sv.child, -1, -1)
+	minWidth, minHeight := width.min, height.min
+	if sv.horizontal {
+		minWidth = 0
+	}
+	if sv.vertical {
+		minHeight = 0
+	}
+	width, height = flexibleSize(minWidth, width.ideal), flexibleSize(minHeight, height.ideal)
+	if sv.width >= 0 {
+		width = fixedSize(sv.width)
+	}
+	if sv.height >= 0 {
+		height = fixedSize(sv.height)
+	}
+	return width, height
+}
+
+// layoutContent finds sizes of content and viewport and which scroll bars
+// are needed. Scroll bars take space of viewport, so one bar can make
+// another needed.
+func (sv *_ScrollView) layoutContent(width, height int) {
+	sv.showV, sv.showH = false, false
+	for i := 0; i < 3; i++ {
+		sv.viewW, sv.viewH = width, height
+		if sv.showV {
+			sv.viewW--
+		}
+		if sv.showH {
+			sv.viewH--
+		}
+		sv.viewW, sv.viewH = max(0, sv.viewW), max(0, sv.viewH)
+		widthRange, _ := measureView(sv.child, sv.viewW, -1)
+		sv.contentW = widthRange.clamp(sv.viewW)
+		if sv.horizontal {
+			sv.contentW = widthRange.clamp(max(sv.viewW, widthRange.ideal))
+		}
+		_, heightRange := measureView(sv.child, sv.contentW, -1)
+		sv.contentH = heightRange.clamp(sv.viewH)
+		if sv.vertical {
+			sv.contentH = heightRange.clamp(max(sv.viewH, heightRange.ideal))
+		}
+		showV := sv.vertical && sv.contentH > sv.viewH
+		showH := sv.horizontal && sv.contentW > sv.viewW
+		if showV == sv.showV && showH == sv.showH {
+			break
+		}
+		sv.showV, sv.showH = showV, showH
+	}
+	sv.offsetX = max(0, min(sv.offsetX, sv.contentW-sv.viewW))
+	sv.offsetY = max(0, min(sv.offsetY, sv.contentH-sv.viewH))
+}
+
+func (sv *_ScrollView) render(width, height int) [][]proto.Cell {
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := sv.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
+	sv.awidth = width
+	sv.aheight = height
+	sv.layoutContent(width, height)
+	canvas := allocateCanvas(width, height)
+	viewport := allocateCanvas(sv.viewW, sv.viewH)
+	placeView(viewport, sv.child, -sv.offsetX, -sv.offsetY, sv.contentW, sv.contentH)
+	for x := 0; x < sv.viewW; x++ {
+		copy(canvas[x], viewport[x])
+	}
+	// Scroll bars
+	theme := themeOrDefault(sv.theme)
+	thumbColor := theme.SecondaryText
+	if sv.focused {
+		thumbColor = theme.Accent
+	}
+	thumbColor, background := sv.style.colors(thumbColor, proto.Color{}, sv.theme)
+	bars := []struct {
+		bar                       *_ScrollBar
+		shown                     bool
+		offset, content, viewport int
+		x, y, width, height       int
+	}{
+		{sv.vbar, sv.showV, sv.offsetY, sv.contentH, sv.viewH, sv.viewW, 0, width - sv.viewW, sv.viewH},
+		{sv.hbar, sv.showH, sv.offsetX, sv.contentW, sv.viewW, 0, sv.viewH, sv.viewW, height - sv.viewH},
+	}
+	for _, b := range bars {
+		if !b.shown {
+			continue
+		}
+		b.bar.offset, b.bar.content, b.bar.viewport = b.offset, b.content, b.viewport
+		b.bar.thumbColor, b.bar.bg = thumbColor, background
+		b.bar.setPos(b.x, b.y)
+		drawView(canvas, b.bar, b.x, b.y, b.width, b.height)
+	}
+	fixWideCells(canvas)
+	return canvas
+}
+
+// scrollBy moves viewport keeping it inside content
+func (sv *_ScrollView) scrollBy(dx, dy int) {
+	sv.offsetX = max(0, min(sv.offsetX+dx, sv.contentW-sv.viewW))
+	sv.offsetY = max(0, min(sv.offsetY+dy, sv.contentH-sv.viewH))
+}
+
+// ScrollTo moves viewport to offset from the top left corner of content
+func (sv *_ScrollView) ScrollTo(x, y int) *_ScrollView {
+	sv.offsetX = max(0, x)
+	sv.offsetY = max(0, y)
+	return sv
+}
+
+func (sv *_ScrollView) subviews() []View {
+	return []View{sv.child}
+}
+
+// getChildrenGestures returns gestures of child clipped by viewport (parts
+// of child out of viewport don't get events), wheel and scroll bar gestures.
+func (sv *_ScrollView) getChildrenGestures(x, y int) []GestureDescriptor {
+	x, y = x+sv.x, y+sv.y
+	actors := make([]GestureDescriptor, 0)
+	sv.wheel.x, sv.wheel.y = 0, 0
+	sv.wheel.width, sv.wheel.height = sv.awidth, sv.aheight
+	actors = append(actors, sv.wheel.getGestureDescriptor(x, y))
+	if viewEnabled(sv.child) {
+		viewport := GestureDescriptor{x: x, y: y, width: sv.viewW, height: sv.viewH}
+		childActors := make([]GestureDescriptor, 0)
+		if sv.child.hasGesture() {
+			childActors = append(childActors, sv.child.getGesture().getGestureDescriptor(x, y))
+		}
+		if asserted, ok := sv.child.(Container); ok {
+			childActors = append(childActors, asserted.getChildrenGestures(x, y)...)
+		}
+		for _, actor := range childActors {
+			if clipped, ok := clipArea(actor, viewport); ok {
+				actors = append(actors, clipped)
+			}
+		}
+	}
+	if sv.showV {
+		actors = append(actors, sv.vbar.getGesture().getGestureDescriptor(x, y))
+	}
+	if sv.showH {
+		actors = append(actors, sv.hbar.getGesture().getGestureDescriptor(x, y))
+	}
+	return actors
+}
+
+// canFocus implements KeyHandler. Scroll view gets focus only if there is
+// something to scroll.
+func (sv *_ScrollView) canFocus() bool {
+	return !sv.disabled && (sv.showV || sv.showH)
+}
+
+// handleKey implements KeyHandler. Arrows scroll by line, PgUp/PgDn by page,
+// Home/End to the beginning and end of content. Keys not used by focused
+// view inside scroll view are passed to it too.
+func (sv *_ScrollView) handleKey(event *proto.EventRequest) bool {
+	if event.Ch != 0 || event.Mod == termbox.ModAlt {
+		return false
+	}
+	page := max(1, sv.viewH-1)
+	switch {
+	case sv.vertical && event.Key == termbox.KeyArrowUp:
+		sv.scrollBy(0, -1)
+	case sv.vertical && event.Key == termbox.KeyArrowDown:
+		sv.scrollBy(0, 1)
+	case sv.horizontal && event.Key == termbox.KeyArrowLeft:
+		sv.scrollBy(-1, 0)
+	case sv.horizontal && event.Key == termbox.KeyArrowRight:
+		sv.scrollBy(1, 0)
+	case sv.vertical && event.Key == termbox.KeyPgup:
+		sv.scrollBy(0, -page)
+	case sv.vertical && event.Key == termbox.KeyPgdn:
+		sv.scrollBy(0, page)
+	case event.Key == termbox.KeyHome:
+		sv.ScrollTo(0, 0)
+	case event.Key == termbox.KeyEnd:
+		sv.ScrollTo(sv.offsetX, sv.contentH)
+	default:
+		return false
+	}
+	return true
+}
+
+// focusChanged implements KeyHandler.
+func (sv *_ScrollView) focusChanged(focused bool) {
+	sv.focused = focused
+}
+
+// Axes sets directions content can be scrolled in (vertical by default)
+func (sv *_ScrollView) Axes(vertical, horizontal bool) *_ScrollView {
+	sv.vertical = vertical
+	sv.horizontal = horizontal
+	return sv
+}
+
+func (sv *_ScrollView) SetSize(width, height int) *_ScrollView {
+	sv.width = width
+	sv.height = height
+	return sv
+}
+
+// Disabled disables (or enables) scroll view and its content
+func (sv *_ScrollView) Disabled(b bool) *_ScrollView {
+	sv.disabled = b
+	return sv
+}
+
+// StyleClass sets stylesheet classes of scroll view
+func (sv *_ScrollView) StyleClass(classes ...string) *_ScrollView {
+	sv.classes = classes
+	return sv
+}
+
+func (sv *_ScrollView) Padding(sides ...int) *_ScrollView {
+	sv.padding = edgesOf(sides...)
+	return sv
+}
+
+func (sv *_ScrollView) Margin(sides ...int) *_ScrollView {
+	sv.margin = edgesOf(sides...)
+	return sv
+}
+
+func (sv *_ScrollView) Flex(weight int) *_ScrollView {
+	sv.setFlex(weight)
+	return sv
+}
+
+func (sv *_ScrollView) LayoutPriority(priority int) *_ScrollView {
+	sv.setPriority(priority)
+	return sv
+}
+
+func (sv *_ScrollView) MinWidth(width int) *_ScrollView {
+	sv.setWidthLimits(max(0, width), -1)
+	return sv
+}
+
+func (sv *_ScrollView) MaxWidth(width int) *_ScrollView {
+	sv.setWidthLimits(-1, max(0, width))
+	return sv
+}
+
+func (sv *_ScrollView) MinHeight(height int) *_ScrollView {
+	sv.setHeightLimits(max(0, height), -1)
+	return sv
+}
+
+func (sv *_ScrollView) MaxHeight(height int) *_ScrollView {
+	sv.setHeightLimits(-1, max(0, height))
+	return sv
+}
+
+// SetView sets view shown in scroll view
+func (sv *_ScrollView) SetView(view View) *_ScrollView {
+	sv.child = viewOrEmpty(view)
+	sv.offsetX, sv.offsetY = 0, 0
+	sv.invalidate()
+	return sv
+}
+
+// RemoveView removes view from scroll view
+func (sv *_ScrollView) RemoveView(view View) *_ScrollView {
+	if view == sv.child {
+		sv.SetView(nil)
+	}
+	return sv
+}
+
+// ReplaceView puts view in place of old one
+func (sv *_ScrollView) ReplaceView(old, view View) *_ScrollView {
+	if old == sv.child {
+		sv.SetView(view)
+	}
+	return sv
+}
+
+// Clear removes view from scroll view
+func (sv *_ScrollView) Clear() *_ScrollView {
+	return sv.SetView(nil)
+}
+
+// Children returns view of scroll view or empty list if it has no view
+func (sv *_ScrollView) Children() []View {
+	return childrenOf(sv.child)
+}
+
+// ScrollView creates vertically scrollable view showing child that can be
+// bigger than the view
+func ScrollView(child View) *_ScrollView {
+	sv := new(_ScrollView)
+	sv.child = viewOrEmpty(child)
+	sv.kind = "ScrollView"
+	sv.width = -1
+	sv.height = -1
+	sv.vertical = true
+	sv.vbar = newScrollBar(true, func(offset int) {
+		sv.scrollBy(0, offset-sv.offsetY)
+	})
+	sv.hbar = newScrollBar(false, func(offset int) {
+		sv.scrollBy(offset-sv.offsetX, 0)
+	})
+	sv.wheel = &wheelGesture{action: func(delta int) {
+		if sv.vertical {
+			sv.scrollBy(0, delta)
+		} else {
+			sv.scrollBy(delta, 0)
+		}
+	}}
+	return sv
+}
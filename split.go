@@ -0,0 +1,563 @@
+package fwsui
+
+import (
+	"math"
+	"time"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+)
+
+// doubleClickInterval – maximal interval between clicks of double click
+const doubleClickInterval = 500 * time.Millisecond
+
+// _SplitDivider – draggable line between panes of split view
+type _SplitDivider struct {
+	x, y, awidth, aheight int
+	split                 *_Split
+	index                 int // panes index and index+1 are resized
+	gesture               *_DragGesture
+	dragging              bool
+	moved                 bool
+	startSizes            []int
+	lastClick             time.Time
+}
+
+func newSplitDivider(split *_Split, index int) *_SplitDivider {
+	divider := new(_SplitDivider)
+	divider.split = split
+	divider.index = index
+	divider.gesture = DragGesture().OnChanged(func(value Value) {
+		translationX, translationY := value.Translation()
+		translation := translationX
+		if split.vertical {
+			translation = translationY
+		}
+		if !divider.dragging {
+			divider.dragging = true
+			divider.startSizes = append([]int(nil), split.sizes...)
+		}
+		if translation == 0 && !divider.moved {
+			return
+		}
+		divider.moved = true
+		split.moveDivider(divider.index, divider.startSizes, translation)
+	}).OnEnded(func(value Value) {
+		divider.dragging = false
+		divider.moved = false
+		translationX, translationY := value.Translation()
+		if translationX != 0 || translationY != 0 {
+			divider.lastClick = time.Time{}
+			return
+		}
+		now := time.Now()
+		if now.Sub(divider.lastClick) < doubleClickInterval {
+			split.toggleCollapse(divider.index)
+			now = time.Time{}
+		}
+		divider.lastClick = now
+	})
+	return divider
+}
+
+func (divider *_SplitDivider) getLogicalSize() (int, int) {
+	if divider.split.vertical {
+		return -1, 1
+	}
+	return 1, -1
+}
+
+func (divider *_SplitDivider) getActualSize() (int, int) {
+	return divider.awidth, divider.aheight
+}
+
+func (divider *_SplitDivider) getPos() (int, int) {
+	return divider.x, divider.y
+}
+
+func (divider *_SplitDivider) setPos(x, y int) {
+	divider.x = x
+	divider.y = y
+}
+
+func (divider *_SplitDivider) hasGesture() bool {
+	return true
+}
+
+func (divider *_SplitDivider) getGesture() Gesture {
+	divider.gesture.setParentViewSizes(divider)
+	return divider.gesture
+}
+
+func (divider *_SplitDivider) render(width, height int) [][]proto.Cell {
+	divider.awidth = width
+	divider.aheight = height
+	canvas := allocateCanvas(width, height)
+	theme := themeOrDefault(divider.split.theme)
+	fg := theme.Border
+	if divider.dragging {
+		fg = theme.Accent
+	}
+	fg, bg := divider.split.style.colors(fg, proto.Color{}, divider.split.theme)
+	ch := '│'
+	if divider.split.vertical {
+		ch = '─'
+	}
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			canvas[x][y] = proto.Cell{Ch: ch, Fg: fg, Bg: bg}
+		}
+	}
+	return canvas
+}
+
+// _Split – container showing children in panes separated by dividers that
+// can be dragged to resize panes. Sizes of panes are kept as ratios of
+// available space, so panes keep proportions when split is resized.
+type _Split struct {
+	x, y, width, height int
+	awidth, aheight     int
+	vertical            bool // panes are placed from top to bottom
+	children            []View
+	dividers            []*_SplitDivider
+	ratios              []float64
+	minSizes            []int
+	sizes               []int // sizes of panes in last layout
+	available           int   // space of panes in last layout
+	collapsed           int   // index of collapsed pane, -1 – none
+	savedRatios         []float64
+	onRatioChanged      func(ratios []float64)
+	theme               *Theme
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
+}
+
+// getGesture implements View.
+func (*_Split) getGesture() Gesture {
+	return nil
+}
+
+// hasGesture implements View.
+func (*_Split) hasGesture() bool {
+	return false
+}
+
+func (split *_Split) getLogicalSize() (int, int) {
+	return split.width, split.height
+}
+
+func (split *_Split) getActualSize() (int, int) {
+	return split.awidth, split.aheight
+}
+
+func (split *_Split) setPos(x, y int) {
+	split.x = x
+	split.y = y
+}
+
+func (split *_Split) getPos() (int, int) {
+	return split.x, split.y
+}
+
+// setTheme implements themedView.
+func (split *_Split) setTheme(theme *Theme) {
+	split.theme = theme
+}
+
+// minSize returns minimal size of pane
+func (split *_Split) minSize(i int) int {
+	if i == split.collapsed || i >= len(split.minSizes) {
+		return 0
+	}
+	return max(0, split.minSizes[i])
+}
+
+// paneSizes returns sizes of panes sharing available space by ratios.
+// Panes smaller than their minimal size take space from other panes in
+// proportion to their sizes.
+func (split *_Split) paneSizes(available int) []int {
+	items := make([]layoutItem, len(split.children))
+	position, total := 0, 0.0
+	for i := range split.children {
+		total += split.ratios[i]
+		next := int(math.Round(total * float64(available)))
+		if i == len(split.children)-1 {
+			next = available
+		}
+		target := max(0, next-position)
+		position = next
+		minSize := split.minSize(i)
+		items[i] = layoutItem{size: sizeRange{minSize, target, max(minSize, target)}, weight: max(1, target)}
+	}
+	return distribute(available, items)
+}
+
+// setRatios sets ratios of panes from their sizes and reports change
+func (split *_Split) setRatios(sizes []int) {
+	if split.available <= 0 {
+		return
+	}
+	for i, size := range sizes {
+		split.ratios[i] = float64(size) / float64(split.available)
+	}
+	split.sizes = sizes
+	if split.onRatioChanged != nil {
+		split.onRatioChanged(split.Ratios())
+	}
+}
+
+// moveDivider moves divider between panes index and index+1 from position
+// it had with panes of start sizes
+func (split *_Split) moveDivider(index int, startSizes []int, translation int) {
+	if index+1 >= len(startSizes) {
+		return
+	}
+	if split.collapsed == index || split.collapsed == index+1 {
+		split.collapsed = -1
+		split.savedRatios = nil
+	}
+	sizes := append([]int(nil), startSizes...)
+	both := startSizes[index] + startSizes[index+1]
+	minBefore, minAfter := split.minSize(index), split.minSize(index+1)
+	sizes[index] = max(min(minBefore, both), min(startSizes[index]+translation, both-minAfter))
+	sizes[index+1] = both - sizes[index]
+	split.setRatios(sizes)
+}
+
+// toggleCollapse collapses the smaller of panes next to divider or restores
+// collapsed pane
+func (split *_Split) toggleCollapse(index int) {
+	if index+1 >= len(split.sizes) {
+		return
+	}
+	if split.collapsed >= 0 {
+		copy(split.ratios, split.savedRatios)
+		split.collapsed = -1
+		split.savedRatios = nil
+	} else {
+		pane, other := index, index+1
+		if split.sizes[other] < split.sizes[pane] {
+			pane, other = other, pane
+		}
+		split.savedRatios = append([]float64(nil), split.ratios...)
+		split.ratios[other] += split.ratios[pane]
+		split.ratios[pane] = 0
+		split.collapsed = pane
+	}
+	if split.onRatioChanged != nil {
+		split.onRatioChanged(split.Ratios())
+	}
+}
+
+func (split *_Split) render(width, height int) [][]proto.Cell {
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := split.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
+	split.awidth = width
+	split.aheight = height
+	canvas := allocateCanvas(width, height)
+	if len(split.children) == 0 {
+		return canvas
+	}
+	length, cross := width, height
+	if split.vertical {
+		length, cross = height, width
+	}
+	split.available = max(0, length-len(split.dividers))
+	split.sizes = split.paneSizes(split.available)
+	position := 0
+	frame := func(v View, position, size int) {
+		if split.vertical {
+			placeView(canvas, v, 0, position, cross, size)
+		} else {
+			placeView(canvas, v, position, 0, size, cross)
+		}
+	}
+	for i, child := range split.children {
+		frame(child, position, split.sizes[i])
+		position += split.sizes[i]
+		if i < len(split.dividers) {
+			frame(split.dividers[i], position, 1)
+			position++
+		}
+	}
+	fixWideCells(canvas)
+	return canvas
+}
+
+// measure implements measurer. Split ideally takes sizes of its children
+// with dividers and can grow.
+func (split *_Split) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	length, cross := fixedSize(len(split.dividers)), fixedSize(0)
+	for i, child := range split.children {
+		w, h := measureView(child, -1, -1)
+		if split.vertical {
+			w, h = h, w
+		}
+		length.min += split.minSize(i)
+		length.ideal += max(split.minSize(i), w.ideal)
+		cross.min = max(cross.min, h.min)
+		cross.ideal = max(cross.ideal, h.ideal)
+	}
+	width, height := flexibleSize(length.min, length.ideal), flexibleSize(cross.min, cross.ideal)
+	if split.vertical {
+		width, height = height, width
+	}
+	if split.width >= 0 {
+		width = fixedSize(split.width)
+	}
+	if split.height >= 0 {
+		height = fixedSize(split.height)
+	}
+	return width, height
+}
+
+func (split *_Split) subviews() []View {
+	return split.children
+}
+
+// getChildrenGestures returns gestures of shown panes and dividers
+func (split *_Split) getChildrenGestures(x, y int) []GestureDescriptor {
+	actors := make([]GestureDescriptor, 0)
+	for i, child := range split.children {
+		if !viewEnabled(child) || i >= len(split.sizes) || split.sizes[i] == 0 {
+			continue
+		}
+		if child.hasGesture() {
+			actors = append(actors, child.getGesture().getGestureDescriptor(x+split.x, y+split.y))
+		}
+		if asserted, ok := child.(Container); ok {
+			actors = append(actors, asserted.getChildrenGestures(x+split.x, y+split.y)...)
+		}
+	}
+	for _, divider := range split.dividers {
+		actors = append(actors, divider.getGesture().getGestureDescriptor(x+split.x, y+split.y))
+	}
+	return actors
+}
+
+// Ratios returns shares of space taken by panes
+func (split *_Split) Ratios() []float64 {
+	return append([]float64(nil), split.ratios...)
+}
+
+// SetRatios sets shares of space taken by panes (e.g. restored ratios
+// reported by OnRatioChanged). Ratios are normalized, missing ones are equal
+// to average of set ones.
+func (split *_Split) SetRatios(ratios ...float64) *_Split {
+	total := 0.0
+	for i := range split.ratios {
+		if i < len(ratios) && ratios[i] > 0 {
+			split.ratios[i] = ratios[i]
+		} else {
+			split.ratios[i] = 1 / float64(len(split.ratios))
+		}
+		total += split.ratios[i]
+	}
+	for i := range split.ratios {
+		split.ratios[i] /= total
+	}
+	split.collapsed = -1
+	split.savedRatios = nil
+	return split
+}
+
+// MinSizes sets minimal sizes of panes along split direction
+func (split *_Split) MinSizes(sizes ...int) *_Split {
+	split.minSizes = sizes
+	return split
+}
+
+// OnRatioChanged sets action called when user resizes or collapses panes
+func (split *_Split) OnRatioChanged(action func(ratios []float64)) *_Split {
+	split.onRatioChanged = action
+	return split
+}
+
+func (split *_Split) SetSize(width, height int) *_Split {
+	split.width = width
+	split.height = height
+	return split
+}
+
+// Disabled disables (or enables) all views of split
+func (split *_Split) Disabled(b bool) *_Split {
+	split.disabled = b
+	return split
+}
+
+// StyleClass sets stylesheet classes of split
+func (split *_Split) StyleClass(classes ...string) *_Split {
+	split.classes = classes
+	return split
+}
+
+func (split *_Split) Padding(sides ...int) *_Split {
+	split.padding = edgesOf(sides...)
+	return split
+}
+
+func (split *_Split) Margin(sides ...int) *_Split {
+	split.margin = edgesOf(sides...)
+	return split
+}
+
+func (split *_Split) Flex(weight int) *_Split {
+	split.setFlex(weight)
+	return split
+}
+
+func (split *_Split) LayoutPriority(priority int) *_Split {
+	split.setPriority(priority)
+	return split
+}
+
+func (split *_Split) MinWidth(width int) *_Split {
+	split.setWidthLimits(max(0, width), -1)
+	return split
+}
+
+func (split *_Split) MaxWidth(width int) *_Split {
+	split.setWidthLimits(-1, max(0, width))
+	return split
+}
+
+func (split *_Split) MinHeight(height int) *_Split {
+	split.setHeightLimits(max(0, height), -1)
+	return split
+}
+
+func (split *_Split) MaxHeight(height int) *_Split {
+	split.setHeightLimits(-1, max(0, height))
+	return split
+}
+
+// resetPanes updates dividers and collapsed state after panes change
+func (split *_Split) resetPanes() {
+	split.dividers = nil
+	for i := 0; i+1 < len(split.children); i++ {
+		split.dividers = append(split.dividers, newSplitDivider(split, i))
+	}
+	split.sizes = nil
+	split.collapsed = -1
+	split.savedRatios = nil
+	split.invalidate()
+}
+
+// AddView adds pane after other panes
+func (split *_Split) AddView(view View) *_Split {
+	return split.InsertView(len(split.children), view)
+}
+
+// InsertView inserts pane at index, new pane takes equal share of space and
+// other panes keep their proportions
+func (split *_Split) InsertView(index int, view View) *_Split {
+	if index < 0 || index > len(split.children) {
+		index = len(split.children)
+	}
+	count := float64(len(split.children) + 1)
+	for i := range split.ratios {
+		split.ratios[i] *= (count - 1) / count
+	}
+	split.ratios = append(split.ratios, 0)
+	copy(split.ratios[index+1:], split.ratios[index:])
+	split.ratios[index] = 1 / count
+	if index < len(split.minSizes) {
+		split.minSizes = append(split.minSizes, 0)
+		copy(split.minSizes[index+1:], split.minSizes[index:])
+		split.minSizes[index] = 0
+	}
+	split.children = insertView(split.children, index, viewOrEmpty(view))
+	split.resetPanes()
+	return split
+}
+
+// RemoveView removes pane with view
+func (split *_Split) RemoveView(view View) *_Split {
+	return split.RemoveAt(indexOfView(split.children, view))
+}
+
+// RemoveAt removes pane at index, other panes share its space in proportion
+// to their sizes
+func (split *_Split) RemoveAt(index int) *_Split {
+	if index < 0 || index >= len(split.children) {
+		return split
+	}
+	split.children = removeViewAt(split.children, index)
+	split.ratios = append(split.ratios[:index], split.ratios[index+1:]...)
+	if index < len(split.minSizes) {
+		split.minSizes = append(split.minSizes[:index], split.minSizes[index+1:]...)
+	}
+	total := 0.0
+	for _, ratio := range split.ratios {
+		total += ratio
+	}
+	for i := range split.ratios {
+		if total > 0 {
+			split.ratios[i] /= total
+		} else {
+			split.ratios[i] = 1 / float64(len(split.ratios))
+		}
+	}
+	split.resetPanes()
+	return split
+}
+
+// ReplaceView puts view in pane of old one
+func (split *_Split) ReplaceView(old, view View) *_Split {
+	if index := indexOfView(split.children, old); index >= 0 {
+		split.children[index] = viewOrEmpty(view)
+		split.invalidate()
+	}
+	return split
+}
+
+// Clear removes all panes
+func (split *_Split) Clear() *_Split {
+	split.children = nil
+	split.ratios = nil
+	split.minSizes = nil
+	split.resetPanes()
+	return split
+}
+
+// Children returns views of panes
+func (split *_Split) Children() []View {
+	return append([]View(nil), split.children...)
+}
+
+func newSplit(vertical bool, children []View) *_Split {
+	split := new(_Split)
+	split.vertical = vertical
+	split.children = make([]View, len(children))
+	for i, child := range children {
+		split.children[i] = viewOrEmpty(child)
+	}
+	split.width = -1
+	split.height = -1
+	split.collapsed = -1
+	split.ratios = make([]float64, len(children))
+	split.resetPanes()
+	split.SetRatios()
+	return split
+}
+
+// HSplit creates split with panes placed side by side, e.g. sidebar that
+// can't be narrower than 10 cells:
+//
+//	HSplit(sidebar, editor).SetRatios(0.25, 0.75).MinSizes(10, 20)
+func HSplit(children ...View) *_Split {
+	split := newSplit(false, children)
+	split.kind = "HSplit"
+	return split
+}
+
+// VSplit creates split with panes placed from top to bottom
+func VSplit(children ...View) *_Split {
+	split := newSplit(true, children)
+	split.kind = "VSplit"
+	return split
+}
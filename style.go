@@ -0,0 +1,471 @@
+package fwsui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+	"github.com/Nekhaevalex/fwsui/color"
+
+	"github.com/nsf/termbox-go"
+)
+
+// InteractionState – state of interactive view. Window tracks it from
+// gestures, keyboard focus and enabled status.
+type InteractionState uint8
+
+const (
+	NormalState   InteractionState = iota
+	HoverState                     // Pointer is over the view
+	PressedState                   // Mouse button is held over the view
+	FocusedState                   // View receives keyboard input
+	DisabledState                  // View doesn't react to input
+)
+
+// stateNames – names of states used in stylesheet selectors
+var stateNames = map[string]InteractionState{
+	"normal":   NormalState,
+	"hover":    HoverState,
+	"pressed":  PressedState,
+	"focused":  FocusedState,
+	"disabled": DisabledState,
+}
+
+// statefulView – implemented by views that are drawn according to their
+// interaction state. State is passed to all views of window before every
+// redraw.
+type statefulView interface {
+	setInteractionState(state InteractionState)
+}
+
+// styleColor – style color: explicit color or theme role
+type styleColor struct {
+	color proto.Color
+	role  ColorRole
+}
+
+// roleNames – names of theme roles used in stylesheets
+var roleNames = map[string]ColorRole{
+	"background":         BackgroundRole,
+	"surface":            SurfaceRole,
+	"text":               TextRole,
+	"secondarytext":      SecondaryTextRole,
+	"accent":             AccentRole,
+	"accenttext":         AccentTextRole,
+	"control":            ControlRole,
+	"controltext":        ControlTextRole,
+	"input":              InputRole,
+	"inputtext":          InputTextRole,
+	"placeholder":        PlaceholderRole,
+	"disabled":           DisabledRole,
+	"disabledtext":       DisabledTextRole,
+	"selection":          SelectionRole,
+	"selectiontext":      SelectionTextRole,
+	"border":             BorderRole,
+	"shadow":             ShadowRole,
+	"titlebar":           TitleBarRole,
+	"titletext":          TitleTextRole,
+	"closebutton":        CloseButtonRole,
+	"closebuttontext":    CloseButtonTextRole,
+	"minimizebutton":     MinimizeButtonRole,
+	"minimizebuttontext": MinimizeButtonTextRole,
+	"maximizebutton":     MaximizeButtonRole,
+	"maximizebuttontext": MaximizeButtonTextRole,
+}
+
+func (c *styleColor) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	name := strings.ToLower(s)
+	if strings.HasPrefix(s, "#") {
+		parsed, err := color.ParseHex(s)
+		if err != nil {
+			return err
+		}
+		c.color = parsed
+		return nil
+	}
+	if role, ok := roleNames[name]; ok {
+		c.role = role
+		return nil
+	}
+	if parsed, ok := color.Basic[name]; ok {
+		c.color = parsed
+		return nil
+	}
+	return fmt.Errorf("unknown color %q", s)
+}
+
+// resolve returns color taking role colors from theme
+func (c *styleColor) resolve(theme *Theme) proto.Color {
+	if c.role != NoRole {
+		return themeOrDefault(theme).Color(c.role)
+	}
+	return c.color
+}
+
+// styleAlign – stylesheet alignment
+type styleAlign Align
+
+func (a *styleAlign) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	switch strings.ToLower(s) {
+	case "left", "top":
+		*a = styleAlign(Left)
+	case "center":
+		*a = styleAlign(Center)
+	case "right", "bottom":
+		*a = styleAlign(Right)
+	default:
+		return fmt.Errorf("unknown alignment %q", s)
+	}
+	return nil
+}
+
+// styleEdges – stylesheet padding: number for all sides or array of sides
+// like in CSS ([vertical, horizontal], [top, right, bottom, left], etc.)
+type styleEdges edges
+
+func (e *styleEdges) UnmarshalJSON(data []byte) error {
+	var sides []int
+	var all int
+	if err := json.Unmarshal(data, &all); err == nil {
+		sides = []int{all}
+	} else if err := json.Unmarshal(data, &sides); err != nil || len(sides) == 0 || len(sides) > 4 {
+		return fmt.Errorf("padding must be number or array of 1-4 numbers")
+	}
+	*e = styleEdges(edgesOf(sides...))
+	return nil
+}
+
+// _Style – set of view properties. Only properties that are set override
+// view properties. Style can have own styles of interaction states.
+type _Style struct {
+	foreground    *styleColor
+	background    *styleColor
+	bold          *bool
+	blink         *bool
+	hidden        *bool
+	dim           *bool
+	underline     *bool
+	cursive       *bool
+	reverse       *bool
+	align         *styleAlign
+	verticalAlign *styleAlign
+	padding       *styleEdges
+	spacing       *int
+	states        map[InteractionState]*_Style
+}
+
+func (style *_Style) UnmarshalJSON(data []byte) error {
+	var decoded struct {
+		Foreground    *styleColor `json:"foreground"`
+		Background    *styleColor `json:"background"`
+		Bold          *bool       `json:"bold"`
+		Blink         *bool       `json:"blink"`
+		Hidden        *bool       `json:"hidden"`
+		Dim           *bool       `json:"dim"`
+		Underline     *bool       `json:"underline"`
+		Cursive       *bool       `json:"cursive"`
+		Reverse       *bool       `json:"reverse"`
+		Align         *styleAlign `json:"align"`
+		VerticalAlign *styleAlign `json:"verticalAlign"`
+		Padding       *styleEdges `json:"padding"`
+		Spacing       *int        `json:"spacing"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&decoded); err != nil {
+		return err
+	}
+	*style = _Style{
+		foreground:    decoded.Foreground,
+		background:    decoded.Background,
+		bold:          decoded.Bold,
+		blink:         decoded.Blink,
+		hidden:        decoded.Hidden,
+		dim:           decoded.Dim,
+		underline:     decoded.Underline,
+		cursive:       decoded.Cursive,
+		reverse:       decoded.Reverse,
+		align:         decoded.Align,
+		verticalAlign: decoded.VerticalAlign,
+		padding:       decoded.Padding,
+		spacing:       decoded.Spacing,
+	}
+	return nil
+}
+
+func (style *_Style) Foreground(c proto.Color) *_Style {
+	style.foreground = &styleColor{color: c}
+	return style
+}
+
+func (style *_Style) Background(c proto.Color) *_Style {
+	style.background = &styleColor{color: c}
+	return style
+}
+
+// ForegroundRole makes foreground follow theme color of role
+func (style *_Style) ForegroundRole(role ColorRole) *_Style {
+	style.foreground = &styleColor{role: role}
+	return style
+}
+
+// BackgroundRole makes background follow theme color of role
+func (style *_Style) BackgroundRole(role ColorRole) *_Style {
+	style.background = &styleColor{role: role}
+	return style
+}
+
+func (style *_Style) Bold(b bool) *_Style {
+	style.bold = &b
+	return style
+}
+
+func (style *_Style) Blink(b bool) *_Style {
+	style.blink = &b
+	return style
+}
+
+func (style *_Style) Hidden(b bool) *_Style {
+	style.hidden = &b
+	return style
+}
+
+func (style *_Style) Dim(b bool) *_Style {
+	style.dim = &b
+	return style
+}
+
+func (style *_Style) Underline(b bool) *_Style {
+	style.underline = &b
+	return style
+}
+
+func (style *_Style) Cursive(b bool) *_Style {
+	style.cursive = &b
+	return style
+}
+
+func (style *_Style) Reverse(b bool) *_Style {
+	style.reverse = &b
+	return style
+}
+
+func (style *_Style) Align(a Align) *_Style {
+	align := styleAlign(a)
+	style.align = &align
+	return style
+}
+
+func (style *_Style) VerticalAlign(a Align) *_Style {
+	align := styleAlign(a)
+	style.verticalAlign = &align
+	return style
+}
+
+// Padding sets padding of view (see Padding of views)
+func (style *_Style) Padding(sides ...int) *_Style {
+	padding := styleEdges(edgesOf(sides...))
+	style.padding = &padding
+	return style
+}
+
+// Spacing sets space between children of stack
+func (style *_Style) Spacing(spacing int) *_Style {
+	style.spacing = &spacing
+	return style
+}
+
+// State sets style used in interaction state
+func (style *_Style) State(state InteractionState, stateStyle *_Style) *_Style {
+	if style.states == nil {
+		style.states = make(map[InteractionState]*_Style)
+	}
+	style.states[state] = stateStyle
+	return style
+}
+
+// Style creates empty style, e.g.
+//
+//	Button("OK", action).StateStyle(HoverState, Style().Background(Blue).Bold(true))
+func Style() *_Style {
+	return new(_Style)
+}
+
+// merge overrides style properties with properties set in other style.
+// States of other style are merged into states of style.
+func (style *_Style) merge(other *_Style) {
+	if other == nil {
+		return
+	}
+	if other.foreground != nil {
+		style.foreground = other.foreground
+	}
+	if other.background != nil {
+		style.background = other.background
+	}
+	if other.bold != nil {
+		style.bold = other.bold
+	}
+	if other.blink != nil {
+		style.blink = other.blink
+	}
+	if other.hidden != nil {
+		style.hidden = other.hidden
+	}
+	if other.dim != nil {
+		style.dim = other.dim
+	}
+	if other.underline != nil {
+		style.underline = other.underline
+	}
+	if other.cursive != nil {
+		style.cursive = other.cursive
+	}
+	if other.reverse != nil {
+		style.reverse = other.reverse
+	}
+	if other.align != nil {
+		style.align = other.align
+	}
+	if other.verticalAlign != nil {
+		style.verticalAlign = other.verticalAlign
+	}
+	if other.padding != nil {
+		style.padding = other.padding
+	}
+	if other.spacing != nil {
+		style.spacing = other.spacing
+	}
+	for state, stateStyle := range other.states {
+		merged := new(_Style)
+		merged.merge(style.states[state])
+		merged.merge(stateStyle)
+		style.State(state, merged)
+	}
+}
+
+// stateStyle returns style of view in state: stylesheet style, own style of
+// state and then stylesheet style of state. Second value reports whether
+// any style of state was found.
+func stateStyle(sheetStyle *_Style, own map[InteractionState]*_Style, state InteractionState) (*_Style, bool) {
+	ownState := own[state]
+	var sheetState *_Style
+	if sheetStyle != nil {
+		sheetState = sheetStyle.states[state]
+	}
+	if state == NormalState || (ownState == nil && sheetState == nil) {
+		return sheetStyle, false
+	}
+	style := new(_Style)
+	style.merge(sheetStyle)
+	style.merge(ownState)
+	style.merge(sheetState)
+	return style, true
+}
+
+// colors returns colors overridden by style
+func (style *_Style) colors(fg, bg proto.Color, theme *Theme) (proto.Color, proto.Color) {
+	if style == nil {
+		return fg, bg
+	}
+	if style.foreground != nil {
+		fg = style.foreground.resolve(theme)
+	}
+	if style.background != nil {
+		bg = style.background.resolve(theme)
+	}
+	return fg, bg
+}
+
+// attributes returns attributes overridden by style
+func (style *_Style) attributes(attr proto.Attr) proto.Attr {
+	if style == nil {
+		return attr
+	}
+	set := func(flag termbox.Attribute, b *bool) {
+		if b == nil {
+			return
+		}
+		if *b {
+			attr = attr | proto.Attr(flag)
+		} else {
+			attr = attr &^ proto.Attr(flag)
+		}
+	}
+	set(termbox.AttrBold, style.bold)
+	set(termbox.AttrBlink, style.blink)
+	set(termbox.AttrHidden, style.hidden)
+	set(termbox.AttrDim, style.dim)
+	set(termbox.AttrUnderline, style.underline)
+	set(termbox.AttrCursive, style.cursive)
+	set(termbox.AttrReverse, style.reverse)
+	return attr
+}
+
+// alignment returns alignment overridden by style
+func (style *_Style) alignment(align, verticalAlign Align) (Align, Align) {
+	if style == nil {
+		return align, verticalAlign
+	}
+	if style.align != nil {
+		align = Align(*style.align)
+	}
+	if style.verticalAlign != nil {
+		verticalAlign = Align(*style.verticalAlign)
+	}
+	return align, verticalAlign
+}
+
+// spacingOr returns spacing overridden by style
+func (style *_Style) spacingOr(spacing int) int {
+	if style == nil || style.spacing == nil {
+		return max(0, spacing)
+	}
+	return max(0, *style.spacing)
+}
+
+// styleable – style selector of view and style resolved for it from
+// stylesheet. Embedded into views that can be styled.
+type styleable struct {
+	kind    string
+	classes []string
+	style   *_Style
+}
+
+func (s *styleable) styleSelector() (string, []string) {
+	return s.kind, s.classes
+}
+
+func (s *styleable) applyStyle(style *_Style) {
+	s.style = style
+}
+
+func (s *styleable) stylePadding() (edges, bool) {
+	if s.style == nil || s.style.padding == nil {
+		return edges{}, false
+	}
+	return edges(*s.style.padding), true
+}
+
+// stylePaddedView – implemented by views which padding can be set by
+// stylesheet
+type stylePaddedView interface {
+	stylePadding() (edges, bool)
+}
+
+// styledView – implemented by views styled by stylesheet. Style is passed to
+// all views of window before every redraw.
+type styledView interface {
+	styleSelector() (kind string, classes []string)
+	applyStyle(style *_Style)
+}
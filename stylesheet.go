@@ -0,0 +1,223 @@
+package fwsui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stylesheet file is a JSON object mapping selectors to styles:
+//
+//	{
+//		"Text":          {"foreground": "#202020"},
+//		"Button":        {"background": "accent", "foreground": "accentText", "bold": true},
+//		"Button.danger": {"background": "#c0392b"},
+//		"Button:hover":  {"underline": true},
+//		".muted":        {"foreground": "secondaryText", "dim": true},
+//		"VStack":        {"padding": [0, 1], "spacing": 1},
+//		"TitleBar":      {"align": "left", "underline": true}
+//	}
+//
+// Selector is a widget kind ("Text", "Button", "TextField", "HStack",
+// "VStack", "ZStack", "Grid", "ScrollView", "AbsoluteLayout", "HSplit",
+// "VSplit", "Tabs", "NavigationStack", "List", "Table", "Tree", "Border"),
+// a style class (".name") or both ("Kind.name"), optionally followed by
+// interaction state (":hover", ":pressed", ":focused", ":disabled"). Window
+// chrome is styled with kinds "Window" (body background), "TitleBar",
+// "CloseButton", "MinimizeButton", "MaximizeButton", "ResizeHandle" and
+// "Shadow". Kind styles are applied first, then class styles and then kind
+// with class styles, so more specific selectors win.
+// State styles override styles without state.
+//
+// Colors are "#rgb", "#rrggbb", "#rrggbbaa", basic color names ("red") or
+// theme color roles ("accent", "controlText") that follow current theme.
+// Alignment is "left", "center" or "right" ("top" and "bottom" can be used
+// for verticalAlign). Padding is a number for all sides or an array of sides
+// like in CSS ([vertical, horizontal] or [top, right, bottom, left]).
+// Spacing is space between stack children.
+
+// _Stylesheet – set of styles loaded from JSON
+type _Stylesheet struct {
+	m        sync.RWMutex
+	rules    map[string]*_Style
+	path     string
+	modTime  time.Time
+	stopChan chan int
+}
+
+// parseRules decodes stylesheet JSON and validates selectors
+func parseRules(data []byte) (map[string]*_Style, error) {
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	rules := make(map[string]*_Style, len(raw))
+	for selector, value := range raw {
+		base, state, hasState := strings.Cut(selector, ":")
+		kind, class, _ := strings.Cut(base, ".")
+		if (kind == "" && class == "") || strings.ContainsAny(class, ". ") || strings.Contains(kind, " ") {
+			return nil, fmt.Errorf("stylesheet: invalid selector %q", selector)
+		}
+		if _, ok := stateNames[state]; hasState && !ok {
+			return nil, fmt.Errorf("stylesheet: unknown state in selector %q", selector)
+		}
+		style := new(_Style)
+		if err := json.Unmarshal(value, style); err != nil {
+			return nil, fmt.Errorf("stylesheet: %q: %w", selector, err)
+		}
+		rules[selector] = style
+	}
+	return rules, nil
+}
+
+// styleFor returns style of view with kind and classes (with styles of
+// states) or nil if no rule matches it
+func (sheet *_Stylesheet) styleFor(kind string, classes []string) *_Style {
+	if sheet == nil {
+		return nil
+	}
+	sheet.m.RLock()
+	defer sheet.m.RUnlock()
+	selectors := make([]string, 0, 1+2*len(classes))
+	selectors = append(selectors, kind)
+	for _, class := range classes {
+		selectors = append(selectors, "."+class)
+	}
+	for _, class := range classes {
+		selectors = append(selectors, kind+"."+class)
+	}
+	var style *_Style
+	apply := func(selector string, state InteractionState, stateful bool) {
+		rule, ok := sheet.rules[selector]
+		if !ok {
+			return
+		}
+		if style == nil {
+			style = new(_Style)
+		}
+		if stateful {
+			style.merge(new(_Style).State(state, rule))
+		} else {
+			style.merge(rule)
+		}
+	}
+	for _, selector := range selectors {
+		apply(selector, NormalState, false)
+	}
+	for name, state := range stateNames {
+		for _, selector := range selectors {
+			apply(selector+":"+name, state, state != NormalState)
+		}
+	}
+	return style
+}
+
+// Reload reads stylesheet file again and restyles windows. Stylesheet keeps
+// previous styles if file can't be read or parsed.
+func (sheet *_Stylesheet) Reload() error {
+	if sheet.path == "" {
+		return nil
+	}
+	info, err := os.Stat(sheet.path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(sheet.path)
+	if err != nil {
+		return err
+	}
+	rules, err := parseRules(data)
+	if err != nil {
+		return err
+	}
+	sheet.m.Lock()
+	sheet.rules = rules
+	sheet.modTime = info.ModTime()
+	sheet.m.Unlock()
+	invalidateScenes()
+	return nil
+}
+
+// Watch checks stylesheet file every interval and reloads it when it
+// changes, so shown windows are restyled live. Errors are logged and
+// previous styles are kept. Stylesheet parsed from data has no file to watch.
+func (sheet *_Stylesheet) Watch(interval time.Duration) *_Stylesheet {
+	sheet.StopWatching()
+	if sheet.path == "" {
+		return sheet
+	}
+	stop := make(chan int)
+	sheet.m.Lock()
+	sheet.stopChan = stop
+	sheet.m.Unlock()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				info, err := os.Stat(sheet.path)
+				if err != nil {
+					continue
+				}
+				sheet.m.RLock()
+				changed := !info.ModTime().Equal(sheet.modTime)
+				sheet.m.RUnlock()
+				if changed {
+					if err := sheet.Reload(); err != nil {
+						log.Printf("stylesheet %s: %v", sheet.path, err)
+						// Don't report the same broken file again
+						sheet.m.Lock()
+						sheet.modTime = info.ModTime()
+						sheet.m.Unlock()
+					}
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return sheet
+}
+
+// StopWatching stops watching stylesheet file
+func (sheet *_Stylesheet) StopWatching() {
+	sheet.m.Lock()
+	defer sheet.m.Unlock()
+	if sheet.stopChan != nil {
+		close(sheet.stopChan)
+		sheet.stopChan = nil
+	}
+}
+
+// invalidateScenes redraws all windows of app if it's running
+func invalidateScenes() {
+	if app := AppInstance(); app != nil {
+		app.invalidateScenes()
+	}
+}
+
+// ParseStylesheet creates stylesheet from JSON data
+func ParseStylesheet(data []byte) (*_Stylesheet, error) {
+	rules, err := parseRules(data)
+	if err != nil {
+		return nil, err
+	}
+	sheet := new(_Stylesheet)
+	sheet.rules = rules
+	return sheet, nil
+}
+
+// LoadStylesheet reads stylesheet from JSON file
+func LoadStylesheet(path string) (*_Stylesheet, error) {
+	sheet := new(_Stylesheet)
+	sheet.path = path
+	if err := sheet.Reload(); err != nil {
+		return nil, err
+	}
+	return sheet, nil
+}
@@ -0,0 +1,99 @@
+package fwsui
+
+import (
+	"testing"
+	"time"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+)
+
+func TestParseRulesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not object", `[1, 2]`},
+		{"empty selector", `{"": {}}`},
+		{"two classes", `{"Button.a.b": {}}`},
+		{"space in selector", `{"VStack Button": {}}`},
+		{"unknown state", `{"Button:active": {}}`},
+		{"unknown property", `{"Button": {"colour": "red"}}`},
+		{"unknown color", `{"Button": {"foreground": "reddish"}}`},
+		{"invalid hex", `{"Button": {"foreground": "#12"}}`},
+		{"unknown alignment", `{"Text": {"align": "middle"}}`},
+		{"too many sides", `{"VStack": {"padding": [1, 2, 3, 4, 5]}}`},
+		{"empty padding", `{"VStack": {"padding": []}}`},
+	}
+	for _, test := range tests {
+		if _, err := parseRules([]byte(test.data)); err == nil {
+			t.Errorf("%s: %s is parsed without error", test.name, test.data)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := parseRules([]byte(`{
+		"Button":        {"foreground": "#ff0000", "bold": true},
+		".muted":        {"foreground": "secondaryText", "dim": true},
+		"Text:hover":    {"underline": true},
+		"VStack":        {"padding": [1, 2], "spacing": 1, "align": "right", "verticalAlign": "top"},
+		"Border.frame":  {"background": "Red", "padding": 3}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 5 {
+		t.Fatalf("%d rules are parsed, want 5", len(rules))
+	}
+	button := rules["Button"]
+	if button.foreground.color != (proto.Color{A: 255, R: 255}) || button.bold == nil || !*button.bold {
+		t.Errorf("Button rule = %+v", button)
+	}
+	if muted := rules[".muted"]; muted.foreground.role != SecondaryTextRole {
+		t.Errorf(".muted foreground role = %v, want SecondaryTextRole", muted.foreground.role)
+	}
+	stack := rules["VStack"]
+	if *stack.padding != (styleEdges{1, 2, 1, 2}) || *stack.spacing != 1 || Align(*stack.align) != Right || Align(*stack.verticalAlign) != Left {
+		t.Errorf("VStack rule = %+v", stack)
+	}
+	frame := rules["Border.frame"]
+	if frame.background.color != (proto.Color{A: 255, R: 255}) || *frame.padding != (styleEdges{3, 3, 3, 3}) {
+		t.Errorf("Border.frame rule = %+v", frame)
+	}
+}
+
+func TestStyleFor(t *testing.T) {
+	sheet, err := ParseStylesheet([]byte(`{
+		"Button":        {"foreground": "red", "bold": true},
+		".danger":       {"foreground": "#00ff00", "dim": true},
+		"Button.danger": {"background": "#0000ff", "dim": false},
+		"Button:hover":  {"underline": true},
+		"Text":          {"bold": false}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	style := sheet.styleFor("Button", []string{"danger"})
+	if style.foreground.color != (proto.Color{A: 255, G: 255}) {
+		t.Errorf("foreground = %v, class style should override kind style", style.foreground.color)
+	}
+	if style.background.color != (proto.Color{A: 255, B: 255}) || *style.dim {
+		t.Errorf("background, dim = %v, %v, kind with class style should win", style.background.color, *style.dim)
+	}
+	if !*style.bold {
+		t.Error("bold of kind style is lost")
+	}
+	if hover := style.states[HoverState]; hover == nil || hover.underline == nil || !*hover.underline {
+		t.Errorf("hover style = %+v", hover)
+	}
+	if style := sheet.styleFor("TextField", nil); style != nil {
+		t.Errorf("style of view without rules = %+v, want nil", style)
+	}
+	if sheet.Watch(time.Millisecond); sheet.stopChan != nil {
+		t.Error("stylesheet without file is watched")
+	}
+	var empty *_Stylesheet
+	if style := empty.styleFor("Button", nil); style != nil {
+		t.Errorf("nil stylesheet gives style %+v", style)
+	}
+}
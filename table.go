@@ -0,0 +1,794 @@
+package fwsui
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+	"github.com/nsf/termbox-go"
+)
+
+// TableDataSource – data shown by Table: amount of rows and text of every
+// cell. Rows are counted in order of source, table sorts them by itself.
+type TableDataSource interface {
+	ListDataSource
+	Cell(row, column int) string
+}
+
+// _TableColumn – definition of table column: title, size and alignment of
+// its cells
+type _TableColumn struct {
+	title    string
+	width    int // exact width, -1 – column shares free width
+	flex     int
+	minWidth int
+	align    Align
+	sortable bool
+	less     func(a, b int) bool
+}
+
+// TableColumn creates column with title. Columns share width of table
+// equally unless Width or Flex is set.
+func TableColumn(title string) *_TableColumn {
+	column := new(_TableColumn)
+	column.title = title
+	column.width = -1
+	column.flex = 1
+	column.minWidth = 1
+	column.sortable = true
+	return column
+}
+
+// Width makes column exactly width cells wide
+func (column *_TableColumn) Width(width int) *_TableColumn {
+	column.width = max(0, width)
+	return column
+}
+
+// Flex makes column take share of width left from other columns in
+// proportion to weight
+func (column *_TableColumn) Flex(weight int) *_TableColumn {
+	column.width = -1
+	column.flex = max(1, weight)
+	return column
+}
+
+// MinWidth sets width column can't be made narrower than
+func (column *_TableColumn) MinWidth(width int) *_TableColumn {
+	column.minWidth = max(0, width)
+	return column
+}
+
+// Align sets alignment of title and cells of column
+func (column *_TableColumn) Align(align Align) *_TableColumn {
+	column.align = align
+	return column
+}
+
+// Sortable sets whether click on column title sorts table (true by default)
+func (column *_TableColumn) Sortable(b bool) *_TableColumn {
+	column.sortable = b
+	return column
+}
+
+// SortFunc sets function comparing rows a and b of source when table is
+// sorted by column. By default texts of cells are compared, as numbers if
+// both are numbers.
+func (column *_TableColumn) SortFunc(less func(a, b int) bool) *_TableColumn {
+	column.less = less
+	return column
+}
+
+// Title returns title of column
+func (column *_TableColumn) Title() string {
+	return column.title
+}
+
+// cellLess compares texts of cells as numbers if both are numbers and as
+// strings otherwise
+func cellLess(a, b string) bool {
+	x, errX := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errY := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errX == nil && errY == nil {
+		return x < y
+	}
+	return a < b
+}
+
+// drawAligned draws line of text aligned inside width cells starting at x,
+// text that doesn't fit is truncated with ellipsis
+func drawAligned(canvas [][]proto.Cell, x, y, width int, text string, align Align, fg, bg proto.Color, attr proto.Attr) {
+	line := truncateLine(splitGraphemes(text), width, TruncateTail, false)
+	switch align {
+	case Center:
+		x += (width - graphemesWidth(line)) / 2
+	case Right:
+		x += width - graphemesWidth(line)
+	}
+	for _, g := range line {
+		x += putGrapheme(canvas, x, y, g, fg, bg, attr)
+	}
+}
+
+// _TableHeader – header row of table. Click on column title sorts table by
+// column, drag of separator after column resizes it.
+type _TableHeader struct {
+	x, y            int
+	awidth, aheight int
+	table           *_Table
+	gesture         *_DragGesture
+	dragging        bool
+	moved           bool
+	pressed         int // column which title is pressed, -1 – none
+	resizing        int // column which separator is dragged, -1 – none
+	startWidth      int
+}
+
+func newTableHeader(table *_Table) *_TableHeader {
+	header := new(_TableHeader)
+	header.table = table
+	header.pressed = -1
+	header.resizing = -1
+	header.gesture = DragGesture().OnChanged(func(value Value) {
+		localX, _ := value.LocalLocation()
+		if !header.dragging {
+			header.dragging = true
+			column, separator := header.columnAt(localX)
+			if separator {
+				header.resizing = column
+				header.startWidth = table.widths[column]
+			} else {
+				header.pressed = column
+			}
+			return
+		}
+		translationX, _ := value.Translation()
+		if translationX == 0 && !header.moved {
+			return
+		}
+		header.moved = true
+		if header.resizing >= 0 {
+			table.resizeColumn(header.resizing, header.startWidth+translationX)
+		}
+	}).OnEnded(func(value Value) {
+		if !header.moved && header.pressed >= 0 {
+			table.toggleSort(header.pressed)
+		}
+		header.dragging, header.moved = false, false
+		header.pressed, header.resizing = -1, -1
+	})
+	return header
+}
+
+// columnAt returns column at x of header and whether x is on separator after
+// the column
+func (header *_TableHeader) columnAt(x int) (int, bool) {
+	table := header.table
+	for i, offset := range table.offsets {
+		switch {
+		case x >= offset && x < offset+table.widths[i]:
+			return i, false
+		case x == offset+table.widths[i]:
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (header *_TableHeader) getLogicalSize() (int, int) {
+	return -1, 1
+}
+
+func (header *_TableHeader) getActualSize() (int, int) {
+	return header.awidth, header.aheight
+}
+
+func (header *_TableHeader) getPos() (int, int) {
+	return header.x, header.y
+}
+
+func (header *_TableHeader) setPos(x, y int) {
+	header.x = x
+	header.y = y
+}
+
+func (header *_TableHeader) hasGesture() bool {
+	return true
+}
+
+func (header *_TableHeader) getGesture() Gesture {
+	header.gesture.setParentViewSizes(header)
+	return header.gesture
+}
+
+// render draws titles of columns with sort mark of sorted column and
+// separators between columns
+func (header *_TableHeader) render(width, height int) [][]proto.Cell {
+	header.awidth = width
+	header.aheight = height
+	canvas := allocateCanvas(width, height)
+	table := header.table
+	theme := themeOrDefault(table.theme)
+	fg, bg := table.style.colors(theme.Text, theme.Surface, table.theme)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			canvas[x][y] = proto.Cell{Ch: ' ', Fg: fg, Bg: bg}
+		}
+	}
+	bold := proto.Attr(termbox.AttrBold)
+	for i, column := range table.columns {
+		x, w := table.offsets[i], table.widths[i]
+		if x >= width {
+			break
+		}
+		if i == table.sortColumn && w >= 2 {
+			mark := '▲'
+			if !table.ascending {
+				mark = '▼'
+			}
+			canvas[min(width-1, x+w-1)][0] = proto.Cell{Ch: mark, Fg: theme.Accent, Bg: bg}
+			w -= 2
+		}
+		drawAligned(canvas, x, 0, min(w, width-x), column.title, column.align, fg, bg, bold)
+		if separator := table.offsets[i] + table.widths[i]; separator < width {
+			separatorColor := theme.Border
+			if i == header.resizing {
+				separatorColor = theme.Accent
+			}
+			canvas[separator][0] = proto.Cell{Ch: '│', Fg: separatorColor, Bg: bg}
+		}
+	}
+	return canvas
+}
+
+// _TableRow – row of table shown by its list: texts of cells or views
+// returned by cell view function placed in columns
+type _TableRow struct {
+	x, y            int
+	awidth, aheight int
+	table           *_Table
+	index           int    // index of row in list
+	row             int    // index of row in source
+	views           []View // views of cells, nil for text cells
+}
+
+func newTableRow(table *_Table, index int) *_TableRow {
+	row := new(_TableRow)
+	row.table = table
+	row.index = index
+	row.row = table.rowOf(index)
+	row.views = make([]View, len(table.columns))
+	if table.cellView != nil {
+		for column := range table.columns {
+			row.views[column] = table.cellView(row.row, column)
+		}
+	}
+	return row
+}
+
+func (row *_TableRow) getLogicalSize() (int, int) {
+	return -1, -1
+}
+
+func (row *_TableRow) getActualSize() (int, int) {
+	return row.awidth, row.aheight
+}
+
+func (row *_TableRow) getPos() (int, int) {
+	return row.x, row.y
+}
+
+func (row *_TableRow) setPos(x, y int) {
+	row.x = x
+	row.y = y
+}
+
+func (*_TableRow) hasGesture() bool {
+	return false
+}
+
+func (*_TableRow) getGesture() Gesture {
+	return nil
+}
+
+// render draws cells of row over background filled by list
+func (row *_TableRow) render(width, height int) [][]proto.Cell {
+	row.awidth = width
+	row.aheight = height
+	canvas := allocateCanvas(width, height)
+	table := row.table
+	theme := themeOrDefault(table.theme)
+	fg := theme.Text
+	if table.list.selected[row.index] {
+		fg = theme.SelectionText
+	}
+	for i, column := range table.columns {
+		if i >= len(table.offsets) || table.offsets[i] >= width {
+			break
+		}
+		x, w := table.offsets[i], min(table.widths[i], width-table.offsets[i])
+		if view := row.views[i]; view != nil {
+			placeView(canvas, view, x, 0, w, height)
+			continue
+		}
+		drawAligned(canvas, x, 0, w, table.source.Cell(row.row, i), column.align, fg, proto.Color{}, 0)
+	}
+	return canvas
+}
+
+// subviews returns views of cells
+func (row *_TableRow) subviews() []View {
+	views := make([]View, 0, len(row.views))
+	for _, view := range row.views {
+		if view != nil {
+			views = append(views, view)
+		}
+	}
+	return views
+}
+
+// getChildrenGestures returns gestures of views of cells clipped to their
+// columns
+func (row *_TableRow) getChildrenGestures(x, y int) []GestureDescriptor {
+	x, y = x+row.x, y+row.y
+	actors := make([]GestureDescriptor, 0)
+	for i, view := range row.views {
+		if view == nil || !viewEnabled(view) || i >= len(row.table.offsets) {
+			continue
+		}
+		cell := GestureDescriptor{x: x + row.table.offsets[i], y: y, width: row.table.widths[i], height: row.aheight}
+		viewActors := make([]GestureDescriptor, 0)
+		if view.hasGesture() {
+			viewActors = append(viewActors, view.getGesture().getGestureDescriptor(x, y))
+		}
+		if asserted, ok := view.(Container); ok {
+			viewActors = append(viewActors, asserted.getChildrenGestures(x, y)...)
+		}
+		for _, actor := range viewActors {
+			if clipped, ok := clipArea(actor, cell); ok {
+				actors = append(actors, clipped)
+			}
+		}
+	}
+	return actors
+}
+
+// tableRows – data source of list showing rows of table
+type tableRows struct {
+	table *_Table
+}
+
+func (rows tableRows) Count() int {
+	return rows.table.source.Count()
+}
+
+func (rows tableRows) Row(index int) View {
+	return newTableRow(rows.table, index)
+}
+
+// _Table – rows of data source shown in columns under header. Rows are
+// virtualized by list, so table can show any number of them.
+type _Table struct {
+	x, y, width, height int
+	awidth, aheight     int
+	source              TableDataSource
+	columns             []*_TableColumn
+	widths              []int
+	offsets             []int
+	header              *_TableHeader
+	list                *_List
+	order               []int // rows of source in order they are shown
+	sortColumn          int   // -1 – rows are shown in order of source
+	ascending           bool
+	cellView            func(row, column int) View
+	onSelectionChanged  func(rows []int)
+	onActivate          func(row int)
+	onDoubleClick       func(row int)
+	onSort              func(column int, ascending bool)
+	theme               *Theme
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
+}
+
+// getGesture implements View.
+func (*_Table) getGesture() Gesture {
+	return nil
+}
+
+// hasGesture implements View.
+func (*_Table) hasGesture() bool {
+	return false
+}
+
+func (table *_Table) getLogicalSize() (int, int) {
+	return table.width, table.height
+}
+
+func (table *_Table) getActualSize() (int, int) {
+	return table.awidth, table.aheight
+}
+
+func (table *_Table) setPos(x, y int) {
+	table.x = x
+	table.y = y
+}
+
+func (table *_Table) getPos() (int, int) {
+	return table.x, table.y
+}
+
+// setTheme implements themedView.
+func (table *_Table) setTheme(theme *Theme) {
+	table.theme = theme
+}
+
+// rowOf returns row of source shown at index of list
+func (table *_Table) rowOf(index int) int {
+	if index >= 0 && index < len(table.order) {
+		return table.order[index]
+	}
+	return index
+}
+
+// positions returns index of list every row of source is shown at
+func (table *_Table) positions() []int {
+	positions := make([]int, len(table.order))
+	for index, row := range table.order {
+		positions[row] = index
+	}
+	return positions
+}
+
+// sortRows puts rows of source in order of sorted column
+func (table *_Table) sortRows() {
+	count := table.source.Count()
+	table.order = make([]int, count)
+	for i := range table.order {
+		table.order[i] = i
+	}
+	if table.sortColumn < 0 || table.sortColumn >= len(table.columns) {
+		return
+	}
+	column := table.sortColumn
+	less := table.columns[column].less
+	if less == nil {
+		less = func(a, b int) bool {
+			return cellLess(table.source.Cell(a, column), table.source.Cell(b, column))
+		}
+	}
+	sort.SliceStable(table.order, func(i, j int) bool {
+		if table.ascending {
+			return less(table.order[i], table.order[j])
+		}
+		return less(table.order[j], table.order[i])
+	})
+}
+
+// reorder sorts rows again keeping the same rows of source selected and
+// current. Selected rows are scrolled to, without selection rows are shown
+// from the first one if top is set.
+func (table *_Table) reorder(top bool) {
+	list := table.list
+	selected := table.Selected()
+	current := table.rowOf(list.cursor)
+	table.sortRows()
+	positions := table.positions()
+	list.selected = make(map[int]bool, len(selected))
+	for _, row := range selected {
+		if row < len(positions) {
+			list.selected[positions[row]] = true
+		}
+	}
+	if current >= 0 && current < len(positions) {
+		list.cursor = positions[current]
+		list.anchor = list.cursor
+	}
+	list.Reload()
+	switch {
+	case len(selected) > 0:
+		list.ScrollTo(list.cursor)
+	case top:
+		list.cursor, list.anchor, list.offset = 0, 0, 0
+	}
+}
+
+// toggleSort sorts table by column, sorted column is sorted in reverse order
+func (table *_Table) toggleSort(column int) {
+	if column < 0 || column >= len(table.columns) || !table.columns[column].sortable {
+		return
+	}
+	ascending := true
+	if column == table.sortColumn {
+		ascending = !table.ascending
+	}
+	table.SortBy(column, ascending)
+}
+
+// resizeColumn makes column exactly width cells wide
+func (table *_Table) resizeColumn(column, width int) {
+	table.columns[column].width = max(table.columns[column].minWidth, width)
+}
+
+// layoutColumns computes widths and positions of columns in width of rows.
+// Columns are separated with one cell.
+func (table *_Table) layoutColumns(width int) {
+	items := make([]layoutItem, len(table.columns))
+	for i, column := range table.columns {
+		if column.width >= 0 {
+			items[i] = layoutItem{size: fixedSize(column.width)}
+		} else {
+			items[i] = layoutItem{size: flexibleSize(column.minWidth, column.minWidth), weight: column.flex}
+		}
+	}
+	table.widths = distribute(max(0, width-max(0, len(items)-1)), items)
+	table.offsets = make([]int, len(items))
+	x := 0
+	for i, w := range table.widths {
+		table.offsets[i] = x
+		x += w + 1
+	}
+}
+
+func (table *_Table) render(width, height int) [][]proto.Cell {
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := table.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
+	table.awidth = width
+	table.aheight = height
+	rowsWidth := width
+	// Scroll bar of list takes the last column
+	if table.list.count()*table.list.rowHeight > height-1 {
+		rowsWidth = max(0, width-1)
+	}
+	table.layoutColumns(rowsWidth)
+	canvas := allocateCanvas(width, height)
+	if height == 0 {
+		return canvas
+	}
+	placeView(canvas, table.header, 0, 0, width, 1)
+	placeView(canvas, table.list, 0, 1, width, height-1)
+	fixWideCells(canvas)
+	return canvas
+}
+
+// measure implements measurer. Table takes all space it is given and
+// ideally fits titles of columns and all its rows.
+func (table *_Table) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	ideal := max(0, len(table.columns)-1)
+	for _, column := range table.columns {
+		if column.width >= 0 {
+			ideal += column.width
+		} else {
+			ideal += max(column.minWidth, stringWidth(column.title)+2)
+		}
+	}
+	width, height := flexibleSize(0, ideal), flexibleSize(1, 1+table.list.count()*table.list.rowHeight)
+	if table.width >= 0 {
+		width = fixedSize(table.width)
+	}
+	if table.height >= 0 {
+		height = fixedSize(table.height)
+	}
+	return width, height
+}
+
+// subviews returns list showing rows
+func (table *_Table) subviews() []View {
+	return []View{table.list}
+}
+
+// getChildrenGestures returns gesture of header and gestures of list
+func (table *_Table) getChildrenGestures(x, y int) []GestureDescriptor {
+	x, y = x+table.x, y+table.y
+	actors := []GestureDescriptor{table.header.getGesture().getGestureDescriptor(x, y)}
+	if !viewEnabled(table.list) {
+		return actors
+	}
+	return append(actors, table.list.getChildrenGestures(x, y)...)
+}
+
+// Selected returns selected rows of source in ascending order
+func (table *_Table) Selected() []int {
+	rows := table.list.Selected()
+	for i, index := range rows {
+		rows[i] = table.rowOf(index)
+	}
+	sort.Ints(rows)
+	return rows
+}
+
+// Select replaces selection with rows of source, the first of them becomes
+// the current row
+func (table *_Table) Select(rows ...int) *_Table {
+	positions := table.positions()
+	indexes := make([]int, 0, len(rows))
+	for _, row := range rows {
+		if row >= 0 && row < len(positions) {
+			indexes = append(indexes, positions[row])
+		}
+	}
+	table.list.Select(indexes...)
+	return table
+}
+
+// ScrollTo scrolls table so that row of source is visible
+func (table *_Table) ScrollTo(row int) *_Table {
+	if positions := table.positions(); row >= 0 && row < len(positions) {
+		table.list.ScrollTo(positions[row])
+	}
+	return table
+}
+
+// SortBy sorts rows by texts (or sort function) of column, negative column
+// shows rows in order of source
+func (table *_Table) SortBy(column int, ascending bool) *_Table {
+	table.sortColumn = max(-1, column)
+	table.ascending = ascending
+	table.reorder(true)
+	if table.onSort != nil {
+		table.onSort(table.sortColumn, ascending)
+	}
+	return table
+}
+
+// Reload sorts rows again and drops views of rows, should be called after
+// data of source changes
+func (table *_Table) Reload() *_Table {
+	table.reorder(false)
+	return table
+}
+
+// CellView sets function building views of cells, cells it returns nil for
+// show text of source. Views are built only for visible rows.
+func (table *_Table) CellView(build func(row, column int) View) *_Table {
+	table.cellView = build
+	table.list.Reload()
+	return table
+}
+
+// RowHeight sets height of every row (1 by default)
+func (table *_Table) RowHeight(height int) *_Table {
+	table.list.RowHeight(height)
+	return table
+}
+
+// SelectionMode sets how many rows can be selected
+func (table *_Table) SelectionMode(mode SelectionMode) *_Table {
+	table.list.SelectionMode(mode)
+	return table
+}
+
+// OnSelectionChanged sets action called with selected rows of source when
+// selection changes
+func (table *_Table) OnSelectionChanged(action func(rows []int)) *_Table {
+	table.onSelectionChanged = action
+	return table
+}
+
+// OnActivate sets action called with row of source when row is activated
+// with Enter or double click
+func (table *_Table) OnActivate(action func(row int)) *_Table {
+	table.onActivate = action
+	return table
+}
+
+// OnDoubleClick sets action called on double click on row (before
+// activation action)
+func (table *_Table) OnDoubleClick(action func(row int)) *_Table {
+	table.onDoubleClick = action
+	return table
+}
+
+// OnSort sets action called when table is sorted by column
+func (table *_Table) OnSort(action func(column int, ascending bool)) *_Table {
+	table.onSort = action
+	return table
+}
+
+// Columns returns columns of table
+func (table *_Table) Columns() []*_TableColumn {
+	return append([]*_TableColumn(nil), table.columns...)
+}
+
+func (table *_Table) SetSize(width, height int) *_Table {
+	table.width = width
+	table.height = height
+	return table
+}
+
+// Disabled disables (or enables) table
+func (table *_Table) Disabled(b bool) *_Table {
+	table.disabled = b
+	return table
+}
+
+// StyleClass sets stylesheet classes of table
+func (table *_Table) StyleClass(classes ...string) *_Table {
+	table.classes = classes
+	return table
+}
+
+func (table *_Table) Padding(sides ...int) *_Table {
+	table.padding = edgesOf(sides...)
+	return table
+}
+
+func (table *_Table) Margin(sides ...int) *_Table {
+	table.margin = edgesOf(sides...)
+	return table
+}
+
+func (table *_Table) Flex(weight int) *_Table {
+	table.setFlex(weight)
+	return table
+}
+
+func (table *_Table) LayoutPriority(priority int) *_Table {
+	table.setPriority(priority)
+	return table
+}
+
+func (table *_Table) MinWidth(width int) *_Table {
+	table.setWidthLimits(max(0, width), -1)
+	return table
+}
+
+func (table *_Table) MaxWidth(width int) *_Table {
+	table.setWidthLimits(-1, max(0, width))
+	return table
+}
+
+func (table *_Table) MinHeight(height int) *_Table {
+	table.setHeightLimits(max(0, height), -1)
+	return table
+}
+
+func (table *_Table) MaxHeight(height int) *_Table {
+	table.setHeightLimits(-1, max(0, height))
+	return table
+}
+
+// Table creates table showing rows of data source in columns:
+//
+//	type users []User
+//
+//	func (u users) Count() int { return len(u) }
+//	func (u users) Cell(row, column int) string {
+//		if column == 0 {
+//			return u[row].Name
+//		}
+//		return strconv.Itoa(u[row].Age)
+//	}
+//
+//	Table(users(list), TableColumn("Name"), TableColumn("Age").Width(5).Align(Right))
+func Table(source TableDataSource, columns ...*_TableColumn) *_Table {
+	table := new(_Table)
+	table.source = source
+	table.columns = columns
+	table.kind = "Table"
+	table.width = -1
+	table.height = -1
+	table.sortColumn = -1
+	table.ascending = true
+	table.header = newTableHeader(table)
+	table.list = List(tableRows{table}).OnSelectionChanged(func([]int) {
+		if table.onSelectionChanged != nil {
+			table.onSelectionChanged(table.Selected())
+		}
+	}).OnActivate(func(index int) {
+		if table.onActivate != nil {
+			table.onActivate(table.rowOf(index))
+		}
+	}).OnDoubleClick(func(index int) {
+		if table.onDoubleClick != nil {
+			table.onDoubleClick(table.rowOf(index))
+		}
+	})
+	table.sortRows()
+	return table
+}
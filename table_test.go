@@ -0,0 +1,103 @@
+package fwsui
+
+import (
+	"reflect"
+	"testing"
+)
+
+// peopleSource – table data source with names and ages
+type peopleSource [][2]string
+
+func (people peopleSource) Count() int { return len(people) }
+
+func (people peopleSource) Cell(row, column int) string { return people[row][column] }
+
+func TestTableSortKeepsSelection(t *testing.T) {
+	people := peopleSource{{"Dan", "40"}, {"Ann", "9"}, {"Cid", "10"}, {"Bob", "25"}}
+	table := Table(people, TableColumn("Name"), TableColumn("Age")).SelectionMode(MultipleSelection)
+	table.list.render(20, 2)
+	table.Select(1, 3)
+	table.SortBy(1, true)
+	if want := []int{1, 2, 3, 0}; !reflect.DeepEqual(table.order, want) {
+		t.Fatalf("order = %v, want %v", table.order, want)
+	}
+	if want := []int{1, 3}; !reflect.DeepEqual(table.Selected(), want) {
+		t.Errorf("selected = %v, want %v", table.Selected(), want)
+	}
+	if want := []int{0, 2}; !reflect.DeepEqual(table.list.Selected(), want) {
+		t.Errorf("selected list rows = %v, want %v", table.list.Selected(), want)
+	}
+	if current := table.rowOf(table.list.cursor); current != 1 {
+		t.Errorf("current row = %d, want 1", current)
+	}
+	table.SortBy(0, false)
+	if want := []int{0, 2, 3, 1}; !reflect.DeepEqual(table.order, want) {
+		t.Fatalf("order = %v, want %v", table.order, want)
+	}
+	if want := []int{1, 3}; !reflect.DeepEqual(table.Selected(), want) {
+		t.Errorf("selected = %v, want %v", table.Selected(), want)
+	}
+	if current := table.rowOf(table.list.cursor); current != 1 {
+		t.Errorf("current row = %d, want 1", current)
+	}
+	// Current row is at the bottom, so it is scrolled to
+	if table.list.offset != 2 {
+		t.Errorf("offset = %d, want 2", table.list.offset)
+	}
+}
+
+func TestTableSortWithoutSelection(t *testing.T) {
+	people := peopleSource{{"Dan", "40"}, {"Ann", "9"}, {"Cid", "10"}, {"Bob", "25"}}
+	table := Table(people, TableColumn("Name"), TableColumn("Age"))
+	table.list.render(20, 2)
+	table.list.scrollBy(2)
+	table.SelectionMode(NoSelection).SortBy(0, true)
+	if table.list.cursor != 0 || table.list.offset != 0 {
+		t.Errorf("cursor, offset = %d, %d, want 0, 0", table.list.cursor, table.list.offset)
+	}
+	table.SortBy(-1, true)
+	if want := []int{0, 1, 2, 3}; !reflect.DeepEqual(table.order, want) {
+		t.Errorf("order = %v, want %v", table.order, want)
+	}
+}
+
+func TestTableReloadDropsRemovedRows(t *testing.T) {
+	people := &peopleRows{{"Dan", "40"}, {"Ann", "9"}, {"Cid", "10"}}
+	table := Table(people, TableColumn("Name"), TableColumn("Age")).SelectionMode(MultipleSelection)
+	table.SortBy(1, true).Select(0, 2)
+	*people = (*people)[:2]
+	table.Reload()
+	if want := []int{1, 0}; !reflect.DeepEqual(table.order, want) {
+		t.Fatalf("order = %v, want %v", table.order, want)
+	}
+	if want := []int{0}; !reflect.DeepEqual(table.Selected(), want) {
+		t.Errorf("selected = %v, want %v", table.Selected(), want)
+	}
+}
+
+func TestTableToggleSort(t *testing.T) {
+	var sorted []int
+	people := peopleSource{{"Ann", "1"}, {"Bob", "2"}}
+	table := Table(people, TableColumn("Name"), TableColumn("Age").Sortable(false)).OnSort(func(column int, ascending bool) {
+		sorted = append(sorted, column)
+	})
+	table.toggleSort(0)
+	if table.sortColumn != 0 || !table.ascending {
+		t.Errorf("column, ascending = %d, %v, want 0, true", table.sortColumn, table.ascending)
+	}
+	table.toggleSort(0)
+	if table.ascending {
+		t.Error("the second click doesn't sort in reverse order")
+	}
+	table.toggleSort(1)
+	if table.sortColumn != 0 || len(sorted) != 2 {
+		t.Errorf("not sortable column is sorted: column %d, sorts %v", table.sortColumn, sorted)
+	}
+}
+
+// peopleRows – table data source that can change between reloads
+type peopleRows [][2]string
+
+func (people *peopleRows) Count() int { return len(*people) }
+
+func (people *peopleRows) Cell(row, column int) string { return (*people)[row][column] }
@@ -0,0 +1,594 @@
+package fwsui
+
+import (
+	proto "github.com/Nekhaevalex/fwsprotocol"
+	"github.com/nsf/termbox-go"
+)
+
+// _Tab – page of Tabs container with its title
+type _Tab struct {
+	title    string
+	view     View
+	closable bool
+	header   *_TabHeader
+}
+
+// Tab creates page of Tabs container
+func Tab(title string, view View) *_Tab {
+	tab := new(_Tab)
+	tab.title = title
+	tab.view = viewOrEmpty(view)
+	return tab
+}
+
+// Closable adds close button to the tab header
+func (tab *_Tab) Closable(b bool) *_Tab {
+	tab.closable = b
+	return tab
+}
+
+// Title returns title of the tab
+func (tab *_Tab) Title() string {
+	return tab.title
+}
+
+// View returns view shown in the tab
+func (tab *_Tab) View() View {
+	return tab.view
+}
+
+// _TabHeader – clickable header of tab in the tab strip. Press selects the
+// tab, click on "×" closes it and drag moves it along the strip.
+type _TabHeader struct {
+	x, y, awidth, aheight int
+	tabs                  *_Tabs
+	tab                   *_Tab
+	gesture               *_DragGesture
+	moved                 bool
+}
+
+func newTabHeader(tabs *_Tabs, tab *_Tab) *_TabHeader {
+	header := new(_TabHeader)
+	header.tabs = tabs
+	header.tab = tab
+	header.gesture = DragGesture().OnChanged(func(value Value) {
+		translationX, _ := value.Translation()
+		localX, _ := value.LocalLocation()
+		if translationX == 0 && !header.moved {
+			// Pressed tab strip gets focus, so keys switch tabs after mouse selection
+			if tabs.host != nil && tabs.canFocus() {
+				tabs.host.setFocus(tabs)
+			}
+			if !header.onCloseButton(localX) {
+				tabs.Select(tabs.indexOf(tab))
+			}
+			return
+		}
+		header.moved = true
+		tabs.Select(tabs.indexOf(tab))
+		if tabs.reorderable {
+			tabs.dragTab(tab, header.x+localX)
+		}
+	}).OnEnded(func(value Value) {
+		moved := header.moved
+		header.moved = false
+		localX, _ := value.LocalLocation()
+		if !moved && header.onCloseButton(localX) {
+			tabs.closeTab(tab)
+		}
+	})
+	return header
+}
+
+// width returns width of header: title with space around it and close button
+func (header *_TabHeader) width() int {
+	width := stringWidth(header.tab.title) + 2
+	if header.tab.closable {
+		width += 2
+	}
+	return width
+}
+
+// onCloseButton reports whether column x of header is its close button
+func (header *_TabHeader) onCloseButton(x int) bool {
+	return header.tab.closable && x == header.awidth-2
+}
+
+func (header *_TabHeader) getLogicalSize() (int, int) {
+	return header.width(), 1
+}
+
+func (header *_TabHeader) getActualSize() (int, int) {
+	return header.awidth, header.aheight
+}
+
+func (header *_TabHeader) getPos() (int, int) {
+	return header.x, header.y
+}
+
+func (header *_TabHeader) setPos(x, y int) {
+	header.x = x
+	header.y = y
+}
+
+func (header *_TabHeader) hasGesture() bool {
+	return true
+}
+
+func (header *_TabHeader) getGesture() Gesture {
+	header.gesture.setParentViewSizes(header)
+	return header.gesture
+}
+
+func (header *_TabHeader) render(width, height int) [][]proto.Cell {
+	header.awidth = width
+	header.aheight = height
+	canvas := allocateCanvas(width, height)
+	tabs := header.tabs
+	theme := themeOrDefault(tabs.theme)
+	fg, bg := tabs.style.colors(theme.SecondaryText, theme.Surface, tabs.theme)
+	var attr proto.Attr
+	if tabs.selectedTab() == header.tab {
+		fg, bg = theme.Text, theme.Background
+		if tabs.focused {
+			fg, bg = theme.SelectionText, theme.Selection
+		}
+		attr = proto.Attr(termbox.AttrBold)
+	}
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			canvas[x][y] = proto.Cell{Ch: ' ', Fg: fg, Bg: bg}
+		}
+	}
+	x := 1
+	for _, g := range splitGraphemes(header.tab.title) {
+		x += putGrapheme(canvas, x, 0, g, fg, bg, attr)
+	}
+	if header.tab.closable && width >= 2 {
+		canvas[width-2][0] = proto.Cell{Ch: '×', Fg: fg, Bg: bg}
+	}
+	return canvas
+}
+
+// _Tabs – container showing one of its tabs under the strip of tab headers.
+// Only views of the selected tab are laid out, get gestures and focus.
+type _Tabs struct {
+	x, y, width, height int
+	awidth, aheight     int
+	tabs                []*_Tab
+	selected            int
+	reorderable         bool
+	focused             bool
+	onSelectionChanged  func(index int)
+	onClose             func(index int) bool
+	onReorder           func(from, to int)
+	theme               *Theme
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
+}
+
+// getGesture implements View.
+func (*_Tabs) getGesture() Gesture {
+	return nil
+}
+
+// hasGesture implements View.
+func (*_Tabs) hasGesture() bool {
+	return false
+}
+
+func (tabs *_Tabs) getLogicalSize() (int, int) {
+	return tabs.width, tabs.height
+}
+
+func (tabs *_Tabs) getActualSize() (int, int) {
+	return tabs.awidth, tabs.aheight
+}
+
+func (tabs *_Tabs) setPos(x, y int) {
+	tabs.x = x
+	tabs.y = y
+}
+
+func (tabs *_Tabs) getPos() (int, int) {
+	return tabs.x, tabs.y
+}
+
+// setTheme implements themedView.
+func (tabs *_Tabs) setTheme(theme *Theme) {
+	tabs.theme = theme
+}
+
+func (tabs *_Tabs) indexOf(tab *_Tab) int {
+	for i, t := range tabs.tabs {
+		if t == tab {
+			return i
+		}
+	}
+	return -1
+}
+
+// selectedTab returns selected tab or nil if there are no tabs
+func (tabs *_Tabs) selectedTab() *_Tab {
+	if tabs.selected < 0 || tabs.selected >= len(tabs.tabs) {
+		return nil
+	}
+	return tabs.tabs[tabs.selected]
+}
+
+// changeSelection selects tab at index and reports it if selected tab or its
+// index has changed (-1 when the last tab is removed). Focus is moved from
+// views of hidden tab to the tabs.
+func (tabs *_Tabs) changeSelection(index int, previous *_Tab, previousIndex int) {
+	tabs.selected = index
+	if tabs.selectedTab() == previous && index == previousIndex {
+		return
+	}
+	if tabs.selectedTab() != previous && previous != nil && tabs.host != nil && tabs.host.focused != nil {
+		var target View
+		if tabs.canFocus() {
+			target = tabs
+		}
+		walkViews(previous.view, func(v View) {
+			if v == tabs.host.focused {
+				tabs.host.setFocus(target)
+			}
+		})
+	}
+	if tabs.onSelectionChanged != nil {
+		tabs.onSelectionChanged(tabs.Selected())
+	}
+}
+
+// Select shows tab at index
+func (tabs *_Tabs) Select(index int) *_Tabs {
+	if index < 0 || index >= len(tabs.tabs) {
+		return tabs
+	}
+	tabs.changeSelection(index, tabs.selectedTab(), tabs.selected)
+	return tabs
+}
+
+// Selected returns index of selected tab (-1 if there are no tabs)
+func (tabs *_Tabs) Selected() int {
+	if len(tabs.tabs) == 0 {
+		return -1
+	}
+	return tabs.selected
+}
+
+// AddTab adds tab after other tabs
+func (tabs *_Tabs) AddTab(tab *_Tab) *_Tabs {
+	return tabs.InsertTab(len(tabs.tabs), tab)
+}
+
+// InsertTab inserts tab at index, index out of range adds tab after other
+// tabs. Selected tab stays selected.
+func (tabs *_Tabs) InsertTab(index int, tab *_Tab) *_Tabs {
+	if index < 0 || index > len(tabs.tabs) {
+		index = len(tabs.tabs)
+	}
+	tab.header = newTabHeader(tabs, tab)
+	previous, previousIndex := tabs.selectedTab(), tabs.selected
+	tabs.tabs = append(tabs.tabs, nil)
+	copy(tabs.tabs[index+1:], tabs.tabs[index:])
+	tabs.tabs[index] = tab
+	selected := 0
+	if previous != nil {
+		selected = tabs.indexOf(previous)
+	}
+	tabs.changeSelection(selected, previous, previousIndex)
+	tabs.invalidate()
+	return tabs
+}
+
+// RemoveView removes tab showing view
+func (tabs *_Tabs) RemoveView(view View) *_Tabs {
+	return tabs.RemoveAt(indexOfView(tabs.Children(), view))
+}
+
+// RemoveAt removes tab at index. If it was selected, the next tab (or the
+// previous one for the last tab) is selected.
+func (tabs *_Tabs) RemoveAt(index int) *_Tabs {
+	if index < 0 || index >= len(tabs.tabs) {
+		return tabs
+	}
+	previous, previousIndex := tabs.selectedTab(), tabs.selected
+	tabs.tabs = append(tabs.tabs[:index], tabs.tabs[index+1:]...)
+	selected := previousIndex
+	if index < selected || selected >= len(tabs.tabs) {
+		selected = max(0, selected-1)
+	}
+	tabs.changeSelection(selected, previous, previousIndex)
+	tabs.invalidate()
+	return tabs
+}
+
+// ReplaceView shows view in tab of old one
+func (tabs *_Tabs) ReplaceView(old, view View) *_Tabs {
+	if index := indexOfView(tabs.Children(), old); index >= 0 {
+		tabs.tabs[index].view = viewOrEmpty(view)
+		tabs.invalidate()
+	}
+	return tabs
+}
+
+// Clear removes all tabs
+func (tabs *_Tabs) Clear() *_Tabs {
+	previous, previousIndex := tabs.selectedTab(), tabs.selected
+	tabs.tabs = nil
+	tabs.changeSelection(0, previous, previousIndex)
+	tabs.invalidate()
+	return tabs
+}
+
+// Children returns views of tabs
+func (tabs *_Tabs) Children() []View {
+	views := make([]View, len(tabs.tabs))
+	for i, tab := range tabs.tabs {
+		views[i] = tab.view
+	}
+	return views
+}
+
+// closeTab removes tab if close action allows it
+func (tabs *_Tabs) closeTab(tab *_Tab) {
+	index := tabs.indexOf(tab)
+	if index < 0 || (tabs.onClose != nil && !tabs.onClose(index)) {
+		return
+	}
+	tabs.RemoveAt(index)
+}
+
+// dragTab moves dragged tab to header under pointer at position x of the
+// strip. Tab is moved only when pointer gets into the place tab takes after
+// moving, so tabs of different widths don't swap back and forth.
+func (tabs *_Tabs) dragTab(tab *_Tab, x int) {
+	from := tabs.indexOf(tab)
+	if from < 0 {
+		return
+	}
+	width := tab.header.awidth
+	to := from
+	for i, other := range tabs.tabs {
+		start, end := other.header.x, other.header.x+other.header.awidth
+		switch {
+		case i > from && x >= end-width:
+			to = i
+		case i < from && x < start+width && to == from:
+			to = i
+		}
+	}
+	if to == from {
+		return
+	}
+	previous, previousIndex := tabs.selectedTab(), tabs.selected
+	tabs.tabs = append(tabs.tabs[:from], tabs.tabs[from+1:]...)
+	tabs.tabs = append(tabs.tabs[:to], append([]*_Tab{tab}, tabs.tabs[to:]...)...)
+	if tabs.onReorder != nil {
+		tabs.onReorder(from, to)
+	}
+	tabs.changeSelection(tabs.indexOf(previous), previous, previousIndex)
+}
+
+func (tabs *_Tabs) render(width, height int) [][]proto.Cell {
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := tabs.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
+	tabs.awidth = width
+	tabs.aheight = height
+	canvas := allocateCanvas(width, height)
+	if height == 0 {
+		return canvas
+	}
+	theme := themeOrDefault(tabs.theme)
+	_, stripBg := tabs.style.colors(theme.SecondaryText, theme.Surface, tabs.theme)
+	for x := 0; x < width; x++ {
+		canvas[x][0] = proto.Cell{Ch: ' ', Bg: stripBg}
+	}
+	x := 0
+	for _, tab := range tabs.tabs {
+		w := tab.header.width()
+		placeView(canvas, tab.header, x, 0, w, 1)
+		x += w
+	}
+	if tab := tabs.selectedTab(); tab != nil {
+		placeView(canvas, tab.view, 0, 1, width, height-1)
+	}
+	fixWideCells(canvas)
+	return canvas
+}
+
+// measure implements measurer. Tabs ideally fit the tab strip and the
+// selected tab under it.
+func (tabs *_Tabs) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	stripWidth := 0
+	for _, tab := range tabs.tabs {
+		stripWidth += tab.header.width()
+	}
+	width, height := flexibleSize(0, stripWidth), flexibleSize(1, 1)
+	if tab := tabs.selectedTab(); tab != nil {
+		w, h := measureView(tab.view, proposedWidth, max(-1, proposedHeight-1))
+		width = flexibleSize(w.min, max(stripWidth, w.ideal))
+		height = flexibleSize(h.min+1, h.ideal+1)
+	}
+	if tabs.width >= 0 {
+		width = fixedSize(tabs.width)
+	}
+	if tabs.height >= 0 {
+		height = fixedSize(tabs.height)
+	}
+	return width, height
+}
+
+// subviews returns view of the selected tab
+func (tabs *_Tabs) subviews() []View {
+	if tab := tabs.selectedTab(); tab != nil {
+		return []View{tab.view}
+	}
+	return nil
+}
+
+// getChildrenGestures returns gestures of visible tab headers and views of
+// the selected tab
+func (tabs *_Tabs) getChildrenGestures(x, y int) []GestureDescriptor {
+	actors := make([]GestureDescriptor, 0)
+	strip := GestureDescriptor{x: x + tabs.x, y: y + tabs.y, width: tabs.awidth, height: 1}
+	for _, tab := range tabs.tabs {
+		area := tab.header.getGesture().getGestureDescriptor(x+tabs.x, y+tabs.y)
+		if area, ok := clipArea(area, strip); ok {
+			actors = append(actors, area)
+		}
+	}
+	tab := tabs.selectedTab()
+	if tab == nil || !viewEnabled(tab.view) {
+		return actors
+	}
+	if tab.view.hasGesture() {
+		actors = append(actors, tab.view.getGesture().getGestureDescriptor(x+tabs.x, y+tabs.y))
+	}
+	if asserted, ok := tab.view.(Container); ok {
+		actors = append(actors, asserted.getChildrenGestures(x+tabs.x, y+tabs.y)...)
+	}
+	return actors
+}
+
+// canFocus implements KeyHandler.
+func (tabs *_Tabs) canFocus() bool {
+	return !tabs.disabled && len(tabs.tabs) > 0
+}
+
+// handleKey implements KeyHandler. Alt+PgUp/PgDn switch tabs from any view
+// inside tabs: they replace usual Ctrl+PgUp/PgDn, as termbox reports only
+// Alt modifier. Arrows switch tabs when tab strip is focused.
+func (tabs *_Tabs) handleKey(event *proto.EventRequest) bool {
+	if event.Ch != 0 || len(tabs.tabs) == 0 {
+		return false
+	}
+	next := func(delta int) {
+		tabs.Select((tabs.selected + delta + len(tabs.tabs)) % len(tabs.tabs))
+	}
+	switch {
+	case event.Mod == termbox.ModAlt && event.Key == termbox.KeyPgup:
+		next(-1)
+	case event.Mod == termbox.ModAlt && event.Key == termbox.KeyPgdn:
+		next(1)
+	case tabs.focused && event.Key == termbox.KeyArrowLeft:
+		next(-1)
+	case tabs.focused && event.Key == termbox.KeyArrowRight:
+		next(1)
+	default:
+		return false
+	}
+	return true
+}
+
+// focusChanged implements KeyHandler.
+func (tabs *_Tabs) focusChanged(focused bool) {
+	tabs.focused = focused
+}
+
+// Reorderable allows moving tabs by dragging their headers
+func (tabs *_Tabs) Reorderable(b bool) *_Tabs {
+	tabs.reorderable = b
+	return tabs
+}
+
+// OnSelectionChanged sets action called with index of selected tab when
+// it changes
+func (tabs *_Tabs) OnSelectionChanged(action func(index int)) *_Tabs {
+	tabs.onSelectionChanged = action
+	return tabs
+}
+
+// OnClose sets action called before closable tab at index is closed, tab is
+// kept open if action returns false
+func (tabs *_Tabs) OnClose(action func(index int) bool) *_Tabs {
+	tabs.onClose = action
+	return tabs
+}
+
+// OnReorder sets action called when tab is dragged from one index to another
+func (tabs *_Tabs) OnReorder(action func(from, to int)) *_Tabs {
+	tabs.onReorder = action
+	return tabs
+}
+
+func (tabs *_Tabs) SetSize(width, height int) *_Tabs {
+	tabs.width = width
+	tabs.height = height
+	return tabs
+}
+
+// Disabled disables (or enables) tabs and all views inside them
+func (tabs *_Tabs) Disabled(b bool) *_Tabs {
+	tabs.disabled = b
+	return tabs
+}
+
+// StyleClass sets stylesheet classes of tabs
+func (tabs *_Tabs) StyleClass(classes ...string) *_Tabs {
+	tabs.classes = classes
+	return tabs
+}
+
+func (tabs *_Tabs) Padding(sides ...int) *_Tabs {
+	tabs.padding = edgesOf(sides...)
+	return tabs
+}
+
+func (tabs *_Tabs) Margin(sides ...int) *_Tabs {
+	tabs.margin = edgesOf(sides...)
+	return tabs
+}
+
+func (tabs *_Tabs) Flex(weight int) *_Tabs {
+	tabs.setFlex(weight)
+	return tabs
+}
+
+func (tabs *_Tabs) LayoutPriority(priority int) *_Tabs {
+	tabs.setPriority(priority)
+	return tabs
+}
+
+func (tabs *_Tabs) MinWidth(width int) *_Tabs {
+	tabs.setWidthLimits(max(0, width), -1)
+	return tabs
+}
+
+func (tabs *_Tabs) MaxWidth(width int) *_Tabs {
+	tabs.setWidthLimits(-1, max(0, width))
+	return tabs
+}
+
+func (tabs *_Tabs) MinHeight(height int) *_Tabs {
+	tabs.setHeightLimits(max(0, height), -1)
+	return tabs
+}
+
+func (tabs *_Tabs) MaxHeight(height int) *_Tabs {
+	tabs.setHeightLimits(-1, max(0, height))
+	return tabs
+}
+
+// Tabs creates container with tabs, the first tab is selected:
+//
+//	Tabs(
+//		Tab("General", general),
+//		Tab("Advanced", advanced),
+//	).OnSelectionChanged(func(index int) { settings.Page = index })
+func Tabs(tabs ...*_Tab) *_Tabs {
+	container := new(_Tabs)
+	container.kind = "Tabs"
+	container.width = -1
+	container.height = -1
+	for _, tab := range tabs {
+		container.AddTab(tab)
+	}
+	return container
+}
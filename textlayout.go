@@ -0,0 +1,336 @@
+package fwsui
+
+import (
+	"strings"
+	"unicode"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+// grapheme – single user-perceived character (grapheme cluster) with the
+// rune that represents it in a cell, the amount of cells it occupies and its
+// byte offset in the source string (-1 for inserted characters like ellipsis)
+type grapheme struct {
+	text   string
+	ch     rune
+	width  int
+	offset int
+}
+
+// cellWidth returns the amount of cells rune occupies on screen. Rules match
+// the ones termbox uses while flushing its buffer, so layout computed here
+// agrees with what is actually drawn.
+func cellWidth(r rune) int {
+	if unicode.IsControl(r) {
+		return 0
+	}
+	w := runewidth.RuneWidth(r)
+	if w == 0 || w == 2 && runewidth.IsAmbiguousWidth(r) {
+		w = 1
+	}
+	return w
+}
+
+// splitGraphemes splits string into grapheme clusters. Cell holds only one
+// rune, so the first rune of the cluster is used to represent it (combining
+// marks are dropped, but never shift the following text).
+func splitGraphemes(s string) []grapheme {
+	result := make([]grapheme, 0, len(s))
+	state := -1
+	offset := 0
+	for len(s) > 0 {
+		var cluster string
+		cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		ch := []rune(cluster)[0]
+		result = append(result, grapheme{text: cluster, ch: ch, width: cellWidth(ch), offset: offset})
+		offset += len(cluster)
+	}
+	return result
+}
+
+// shiftGraphemes adds base to source offsets of graphemes
+func shiftGraphemes(clusters []grapheme, base int) []grapheme {
+	for i := range clusters {
+		if clusters[i].offset >= 0 {
+			clusters[i].offset += base
+		}
+	}
+	return clusters
+}
+
+// graphemesWidth returns total width of graphemes in cells
+func graphemesWidth(clusters []grapheme) int {
+	width := 0
+	for _, g := range clusters {
+		width += g.width
+	}
+	return width
+}
+
+// joinGraphemes builds string back from grapheme clusters
+func joinGraphemes(clusters []grapheme) string {
+	s := ""
+	for _, g := range clusters {
+		s += g.text
+	}
+	return s
+}
+
+// stringWidth returns width of string in cells
+func stringWidth(s string) int {
+	return graphemesWidth(splitGraphemes(s))
+}
+
+// graphemeCount returns amount of grapheme clusters in string
+func graphemeCount(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
+
+// graphemeAtColumn returns index of grapheme that covers cell column col.
+// Columns past the end of text give len(clusters).
+func graphemeAtColumn(clusters []grapheme, col int) int {
+	x := 0
+	for i, g := range clusters {
+		if col < x+g.width {
+			return i
+		}
+		x += g.width
+	}
+	return len(clusters)
+}
+
+// putGrapheme writes grapheme into canvas at column x, row y and returns
+// amount of cells it takes. Wide graphemes put zero rune into the following
+// cell the same way termbox does; graphemes cut by canvas borders are
+// replaced with spaces.
+func putGrapheme(canvas [][]proto.Cell, x, y int, g grapheme, fg, bg proto.Color, attr proto.Attr) int {
+	width := len(canvas)
+	if g.width == 0 || y < 0 || len(canvas) == 0 || y >= len(canvas[0]) {
+		return g.width
+	}
+	clipped := x < 0 || x+g.width > width
+	for i := 0; i < g.width; i++ {
+		cx := x + i
+		if cx < 0 || cx >= width {
+			continue
+		}
+		cell := proto.Cell{Fg: fg, Bg: bg, Attribute: attr}
+		switch {
+		case clipped:
+			cell.Ch = ' '
+		case i == 0:
+			cell.Ch = g.ch
+		default:
+			cell.Ch = 0
+		}
+		canvas[cx][y] = cell
+	}
+	return g.width
+}
+
+// fixWideCells removes halves of wide characters left after composing
+// several layers: wide rune whose continuation cell was overwritten by
+// another character is replaced with space.
+func fixWideCells(canvas [][]proto.Cell) {
+	width := len(canvas)
+	for x := 0; x < width; x++ {
+		for y := range canvas[x] {
+			if cellWidth(canvas[x][y].Ch) < 2 {
+				continue
+			}
+			if x+1 >= width || canvas[x+1][y].Ch != 0 {
+				canvas[x][y].Ch = ' '
+			}
+		}
+	}
+}
+
+// WrapMode – defines how text that doesn't fit its width is split into lines
+type WrapMode uint8
+
+const (
+	NoWrap   WrapMode = iota // Lines are split only by explicit newlines
+	WordWrap                 // Lines are split at word boundaries
+	CharWrap                 // Lines are split at any grapheme cluster
+)
+
+// Truncation – defines which part of text is replaced with ellipsis when
+// text doesn't fit its area
+type Truncation uint8
+
+const (
+	TruncateNone   Truncation = iota // Text is cut off at the border
+	TruncateHead                     // "…end of text"
+	TruncateMiddle                   // "beginning…end"
+	TruncateTail                     // "beginning of…"
+)
+
+var ellipsis = grapheme{text: "…", ch: '…', width: cellWidth('…'), offset: -1}
+
+// trimTrailingSpaces removes whitespace left at the end of wrapped line
+func trimTrailingSpaces(line []grapheme) []grapheme {
+	for len(line) > 0 && unicode.IsSpace(line[len(line)-1].ch) {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+// charWrap splits graphemes into lines no wider than width
+func charWrap(clusters []grapheme, width int) [][]grapheme {
+	lines := make([][]grapheme, 0, 1)
+	line := make([]grapheme, 0)
+	lineWidth := 0
+	for _, g := range clusters {
+		if lineWidth+g.width > width && len(line) > 0 {
+			lines = append(lines, line)
+			line = make([]grapheme, 0)
+			lineWidth = 0
+		}
+		line = append(line, g)
+		lineWidth += g.width
+	}
+	return append(lines, line)
+}
+
+// wordWrap splits paragraph into lines no wider than width breaking at line
+// break opportunities. Words wider than line are split by characters.
+func wordWrap(paragraph string, width int) [][]grapheme {
+	lines := make([][]grapheme, 0, 1)
+	line := make([]grapheme, 0)
+	lineWidth := 0
+	state := -1
+	offset := 0
+	for len(paragraph) > 0 {
+		var segment string
+		segment, paragraph, _, state = uniseg.FirstLineSegmentInString(paragraph, state)
+		word := shiftGraphemes(splitGraphemes(segment), offset)
+		offset += len(segment)
+		wordWidth := graphemesWidth(trimTrailingSpaces(word))
+		if lineWidth+wordWidth > width && len(line) > 0 {
+			lines = append(lines, trimTrailingSpaces(line))
+			line = make([]grapheme, 0)
+			lineWidth = 0
+		}
+		if wordWidth > width {
+			parts := charWrap(word, width)
+			lines = append(lines, parts[:len(parts)-1]...)
+			word = parts[len(parts)-1]
+		}
+		line = append(line, word...)
+		lineWidth = graphemesWidth(line)
+	}
+	return append(lines, trimTrailingSpaces(line))
+}
+
+// layoutLines splits text into lines according to wrap mode. Negative width
+// means that there is no width limit.
+func layoutLines(s string, width int, wrap WrapMode) [][]grapheme {
+	lines := make([][]grapheme, 0, 1)
+	offset := 0
+	for _, raw := range strings.Split(s, "\n") {
+		paragraph := strings.TrimSuffix(raw, "\r")
+		var wrapped [][]grapheme
+		switch {
+		case wrap == NoWrap || width < 0:
+			wrapped = [][]grapheme{splitGraphemes(paragraph)}
+		case wrap == WordWrap:
+			wrapped = wordWrap(paragraph, max(width, 1))
+		case wrap == CharWrap:
+			wrapped = charWrap(splitGraphemes(paragraph), max(width, 1))
+		}
+		for _, line := range wrapped {
+			lines = append(lines, shiftGraphemes(line, offset))
+		}
+		// Paragraph is followed by the newline
+		offset += len(raw) + 1
+	}
+	return lines
+}
+
+// truncateLine shortens line to given width replacing cut part with ellipsis.
+// If forced is true ellipsis is added even if line fits (used when some of
+// the following lines were dropped).
+func truncateLine(line []grapheme, width int, mode Truncation, forced bool) []grapheme {
+	if mode == TruncateNone || (!forced && graphemesWidth(line) <= width) {
+		return line
+	}
+	if width < ellipsis.width {
+		return []grapheme{}
+	}
+	free := width - ellipsis.width
+	// Takes graphemes from the beginning until they fit n cells
+	head := func(n int) []grapheme {
+		w := 0
+		for i, g := range line {
+			if w+g.width > n {
+				return line[:i]
+			}
+			w += g.width
+		}
+		return line
+	}
+	// Takes graphemes from the end until they fit n cells
+	tail := func(n int) []grapheme {
+		w := 0
+		for i := len(line) - 1; i >= 0; i-- {
+			if w+line[i].width > n {
+				return line[i+1:]
+			}
+			w += line[i].width
+		}
+		return line
+	}
+	// Ellipsis takes source offset (and so the style) of the first cut
+	// grapheme
+	cut := func(i int) grapheme {
+		mark := ellipsis
+		if len(line) > 0 {
+			mark.offset = line[min(max(i, 0), len(line)-1)].offset
+		}
+		return mark
+	}
+	result := make([]grapheme, 0, len(line)+1)
+	switch mode {
+	case TruncateHead:
+		kept := tail(free)
+		result = append(append(result, cut(len(line)-len(kept)-1)), kept...)
+	case TruncateMiddle:
+		kept := head(free - free/2)
+		result = append(append(append(result, kept...), cut(len(kept))), tail(free/2)...)
+	case TruncateTail:
+		kept := trimTrailingSpaces(head(free))
+		result = append(append(result, kept...), cut(len(kept)))
+	}
+	return result
+}
+
+// fitLines limits amount of lines to maxLines and truncates lines that are
+// wider than width. Head truncation keeps the last lines, other modes keep
+// the first ones.
+func fitLines(lines [][]grapheme, width, maxLines int, mode Truncation) [][]grapheme {
+	dropped := false
+	if maxLines >= 0 && len(lines) > maxLines {
+		dropped = true
+		if mode == TruncateHead {
+			lines = lines[len(lines)-maxLines:]
+		} else {
+			lines = lines[:maxLines]
+		}
+	}
+	result := make([][]grapheme, len(lines))
+	for i, line := range lines {
+		forced := false
+		if dropped {
+			forced = (mode == TruncateHead && i == 0) || (mode != TruncateHead && i == len(lines)-1)
+		}
+		if forced && mode == TruncateMiddle {
+			// Lines after the last visible one are treated as the middle part
+			result[i] = truncateLine(line, width, TruncateTail, forced)
+			continue
+		}
+		result[i] = truncateLine(line, width, mode, forced)
+	}
+	return result
+}
@@ -0,0 +1,136 @@
+package fwsui
+
+import (
+	"reflect"
+	"testing"
+
+	proto "github.com/Nekhaevalex/fwsprotocol"
+)
+
+// lineStrings converts laid out lines back to strings
+func lineStrings(lines [][]grapheme) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		for _, g := range line {
+			result[i] += g.text
+		}
+	}
+	return result
+}
+
+// canvasLines returns rows of canvas as strings, empty cells are spaces
+func canvasLines(canvas [][]proto.Cell) []string {
+	if len(canvas) == 0 {
+		return nil
+	}
+	lines := make([]string, len(canvas[0]))
+	for y := range lines {
+		for x := range canvas {
+			ch := canvas[x][y].Ch
+			if ch == 0 {
+				ch = ' '
+			}
+			lines[y] += string(ch)
+		}
+	}
+	return lines
+}
+
+func TestLayoutLines(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		wrap  WrapMode
+		want  []string
+	}{
+		{"hello world", 20, WordWrap, []string{"hello world"}},
+		{"hello world foo bar", 8, WordWrap, []string{"hello", "world", "foo bar"}},
+		{"hello world", 8, NoWrap, []string{"hello world"}},
+		{"hello world", -1, WordWrap, []string{"hello world"}},
+		{"abcdefgh", 3, CharWrap, []string{"abc", "def", "gh"}},
+		{"a verylongword", 4, WordWrap, []string{"a", "very", "long", "word"}},
+		{"one\ntwo three", 5, WordWrap, []string{"one", "two", "three"}},
+		{"line\r\n", 10, NoWrap, []string{"line", ""}},
+		{"日本語テキスト", 6, CharWrap, []string{"日本語", "テキス", "ト"}},
+	}
+	for _, test := range tests {
+		got := lineStrings(layoutLines(test.text, test.width, test.wrap))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("layoutLines(%q, %d, %d) = %q, want %q", test.text, test.width, test.wrap, got, test.want)
+		}
+	}
+}
+
+func TestWordWrapOffsets(t *testing.T) {
+	lines := layoutLines("ab cd\nef", 3, WordWrap)
+	want := [][]int{{0, 1}, {3, 4}, {6, 7}}
+	for i, line := range lines {
+		offsets := make([]int, len(line))
+		for j, g := range line {
+			offsets[j] = g.offset
+		}
+		if !reflect.DeepEqual(offsets, want[i]) {
+			t.Errorf("offsets of line %d = %v, want %v", i, offsets, want[i])
+		}
+	}
+}
+
+func TestTruncateLine(t *testing.T) {
+	tests := []struct {
+		text   string
+		width  int
+		mode   Truncation
+		forced bool
+		want   string
+	}{
+		{"hello world", 20, TruncateTail, false, "hello world"},
+		{"hello world", 8, TruncateNone, false, "hello world"},
+		{"hello world", 8, TruncateTail, false, "hello w…"},
+		{"hello world", 7, TruncateTail, false, "hello…"},
+		{"hello world", 8, TruncateHead, false, "…o world"},
+		{"hello world", 8, TruncateMiddle, false, "hell…rld"},
+		{"hello", 10, TruncateTail, true, "hello…"},
+		{"hello", 0, TruncateTail, false, ""},
+		{"日本語テキスト", 6, TruncateTail, false, "日本…"},
+	}
+	for _, test := range tests {
+		got := lineStrings([][]grapheme{truncateLine(splitGraphemes(test.text), test.width, test.mode, test.forced)})[0]
+		if got != test.want {
+			t.Errorf("truncateLine(%q, %d, %d, %v) = %q, want %q", test.text, test.width, test.mode, test.forced, got, test.want)
+		}
+	}
+}
+
+func TestFitLines(t *testing.T) {
+	lines := layoutLines("one two three four", 5, WordWrap)
+	if got, want := lineStrings(fitLines(lines, 5, 2, TruncateTail)), []string{"one", "two…"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tail = %q, want %q", got, want)
+	}
+	if got, want := lineStrings(fitLines(lines, 5, 2, TruncateHead)), []string{"…hree", "four"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("head = %q, want %q", got, want)
+	}
+}
+
+func TestTextShrinksInNarrowStack(t *testing.T) {
+	tests := []struct {
+		text   *_Text
+		height int
+		want   []string
+	}{
+		{Text("hello world foo bar").Wrap(WordWrap), 3, []string{"hello   ", "world   ", "foo bar "}},
+		{Text("hello world foo bar").Truncate(TruncateTail), 1, []string{"hello w…"}},
+		{AttributedText(Attributed(Span("hello "), Span("world foo bar").Bold(true))).Wrap(WordWrap), 3, []string{"hello   ", "world   ", "foo bar "}},
+	}
+	for _, test := range tests {
+		got := canvasLines(VStack(test.text).render(8, test.height))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q in 8 columns = %q, want %q", test.text.text, got, test.want)
+		}
+	}
+	// Text without wrap and truncation and text with set size keep width
+	for _, text := range []*_Text{Text("hello world"), Text("hello world").Wrap(WordWrap).SetSize(11, 0)} {
+		if width, _ := measureView(text, 8, -1); !width.fixed() || width.min != 11 {
+			t.Errorf("%q measures %v in 8 columns, want fixed 11", text.text, width)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package fwsui
+
+import (
+	proto "github.com/Nekhaevalex/fwsprotocol"
+	"github.com/Nekhaevalex/fwsui/color"
+)
+
+// ColorRole – semantic color of theme. Views with color roles take colors
+// from theme of the window they are shown in.
+type ColorRole uint8
+
+const (
+	NoRole ColorRole = iota // Color is set explicitly
+	BackgroundRole
+	SurfaceRole
+	TextRole
+	SecondaryTextRole
+	AccentRole
+	AccentTextRole
+	ControlRole
+	ControlTextRole
+	InputRole
+	InputTextRole
+	PlaceholderRole
+	DisabledRole
+	DisabledTextRole
+	SelectionRole
+	SelectionTextRole
+	BorderRole
+	ShadowRole
+	TitleBarRole
+	TitleTextRole
+	CloseButtonRole
+	CloseButtonTextRole
+	MinimizeButtonRole
+	MinimizeButtonTextRole
+	MaximizeButtonRole
+	MaximizeButtonTextRole
+)
+
+// Theme – set of semantic colors used by views and window chrome
+type Theme struct {
+	Background         proto.Color // Window body
+	Surface            proto.Color // Panels placed over background
+	Text               proto.Color
+	SecondaryText      proto.Color
+	Accent             proto.Color
+	AccentText         proto.Color
+	Control            proto.Color // Buttons
+	ControlText        proto.Color
+	Input              proto.Color // Text fields
+	InputText          proto.Color
+	Placeholder        proto.Color
+	Disabled           proto.Color
+	DisabledText       proto.Color
+	Selection          proto.Color
+	SelectionText      proto.Color
+	Border             proto.Color
+	Shadow             proto.Color
+	TitleBar           proto.Color
+	TitleText          proto.Color
+	CloseButton        proto.Color
+	CloseButtonText    proto.Color
+	MinimizeButton     proto.Color
+	MinimizeButtonText proto.Color
+	MaximizeButton     proto.Color
+	MaximizeButtonText proto.Color
+}
+
+// Color returns theme color of role
+func (theme *Theme) Color(role ColorRole) proto.Color {
+	switch role {
+	case BackgroundRole:
+		return theme.Background
+	case SurfaceRole:
+		return theme.Surface
+	case TextRole:
+		return theme.Text
+	case SecondaryTextRole:
+		return theme.SecondaryText
+	case AccentRole:
+		return theme.Accent
+	case AccentTextRole:
+		return theme.AccentText
+	case ControlRole:
+		return theme.Control
+	case ControlTextRole:
+		return theme.ControlText
+	case InputRole:
+		return theme.Input
+	case InputTextRole:
+		return theme.InputText
+	case PlaceholderRole:
+		return theme.Placeholder
+	case DisabledRole:
+		return theme.Disabled
+	case DisabledTextRole:
+		return theme.DisabledText
+	case SelectionRole:
+		return theme.Selection
+	case SelectionTextRole:
+		return theme.SelectionText
+	case BorderRole:
+		return theme.Border
+	case ShadowRole:
+		return theme.Shadow
+	case TitleBarRole:
+		return theme.TitleBar
+	case TitleTextRole:
+		return theme.TitleText
+	case CloseButtonRole:
+		return theme.CloseButton
+	case CloseButtonTextRole:
+		return theme.CloseButtonText
+	case MinimizeButtonRole:
+		return theme.MinimizeButton
+	case MinimizeButtonTextRole:
+		return theme.MinimizeButtonText
+	case MaximizeButtonRole:
+		return theme.MaximizeButton
+	case MaximizeButtonTextRole:
+		return theme.MaximizeButtonText
+	}
+	return proto.Color{}
+}
+
+// LightTheme – default theme
+var LightTheme = &Theme{
+	Background:         White,
+	Surface:            color.Hex("#ececec"),
+	Text:               Black,
+	SecondaryText:      Grey,
+	Accent:             Blue,
+	AccentText:         White,
+	Control:            Grey,
+	ControlText:        White,
+	Input:              LightGrey,
+	InputText:          Black,
+	Placeholder:        Grey,
+	Disabled:           color.Hex("#d8d8d8"),
+	DisabledText:       color.Hex("#a0a0a0"),
+	Selection:          Blue,
+	SelectionText:      White,
+	Border:             Black,
+	Shadow:             color.WithAlpha(Black, 127),
+	TitleBar:           Grey,
+	TitleText:          White,
+	CloseButton:        Red,
+	CloseButtonText:    White,
+	MinimizeButton:     Yellow,
+	MinimizeButtonText: Grey,
+	MaximizeButton:     Green,
+	MaximizeButtonText: White,
+}
+
+// DarkTheme – dark variant of default theme
+var DarkTheme = &Theme{
+	Background:         color.Hex("#1e1e1e"),
+	Surface:            color.Hex("#2a2a2a"),
+	Text:               color.Hex("#e6e6e6"),
+	SecondaryText:      color.Hex("#9a9a9a"),
+	Accent:             color.Hex("#3d8bfd"),
+	AccentText:         White,
+	Control:            color.Hex("#454545"),
+	ControlText:        color.Hex("#f0f0f0"),
+	Input:              color.Hex("#333333"),
+	InputText:          color.Hex("#e6e6e6"),
+	Placeholder:        color.Hex("#7a7a7a"),
+	Disabled:           color.Hex("#2c2c2c"),
+	DisabledText:       color.Hex("#666666"),
+	Selection:          color.Hex("#264f78"),
+	SelectionText:      White,
+	Border:             color.Hex("#6a6a6a"),
+	Shadow:             color.WithAlpha(Black, 160),
+	TitleBar:           color.Hex("#3a3a3a"),
+	TitleText:          color.Hex("#e6e6e6"),
+	CloseButton:        color.Hex("#e5534b"),
+	CloseButtonText:    White,
+	MinimizeButton:     color.Hex("#d4a72c"),
+	MinimizeButtonText: color.Hex("#3a3a3a"),
+	MaximizeButton:     color.Hex("#46954a"),
+	MaximizeButtonText: White,
+}
+
+// themedView – implemented by views that take colors from theme. Theme is
+// passed to all views of window before every redraw.
+type themedView interface {
+	setTheme(theme *Theme)
+}
+
+// themeOrDefault returns theme or LightTheme if theme is not set yet
+func themeOrDefault(theme *Theme) *Theme {
+	if theme == nil {
+		return LightTheme
+	}
+	return theme
+}
@@ -0,0 +1,519 @@
+package fwsui
+
+import (
+	proto "github.com/Nekhaevalex/fwsprotocol"
+	"github.com/nsf/termbox-go"
+)
+
+// _TreeNode – node of tree with title, optional data and child nodes
+type _TreeNode struct {
+	title    string
+	data     any
+	children []*_TreeNode
+	parent   *_TreeNode
+	depth    int
+	lazy     bool // children are loaded by tree on the first expansion
+	expanded bool
+}
+
+// TreeNode creates node with title and child nodes
+func TreeNode(title string, children ...*_TreeNode) *_TreeNode {
+	node := new(_TreeNode)
+	node.title = title
+	return node.Add(children...)
+}
+
+// Add appends child nodes to node
+func (node *_TreeNode) Add(children ...*_TreeNode) *_TreeNode {
+	for _, child := range children {
+		child.parent = node
+	}
+	node.children = append(node.children, children...)
+	return node
+}
+
+// Lazy marks node as having children that are loaded by loader of tree when
+// node is expanded for the first time
+func (node *_TreeNode) Lazy() *_TreeNode {
+	node.lazy = true
+	return node
+}
+
+// SetData attaches value to node (e.g. path of file)
+func (node *_TreeNode) SetData(data any) *_TreeNode {
+	node.data = data
+	return node
+}
+
+// Data returns value attached to node
+func (node *_TreeNode) Data() any {
+	return node.data
+}
+
+// Title returns title of node
+func (node *_TreeNode) Title() string {
+	return node.title
+}
+
+// Children returns child nodes (loaded so far for lazy nodes)
+func (node *_TreeNode) Children() []*_TreeNode {
+	return append([]*_TreeNode(nil), node.children...)
+}
+
+// Parent returns parent node, nil for roots
+func (node *_TreeNode) Parent() *_TreeNode {
+	return node.parent
+}
+
+// Expanded reports whether children of node are shown
+func (node *_TreeNode) Expanded() bool {
+	return node.expanded
+}
+
+// expandable reports whether node has (or may load) children
+func (node *_TreeNode) expandable() bool {
+	return node.lazy || len(node.children) > 0
+}
+
+// last reports whether node is the last child of its parent
+func (node *_TreeNode) last() bool {
+	return node.parent == nil || node.parent.children[len(node.parent.children)-1] == node
+}
+
+// treeRows – data source of list drawing visible nodes of tree
+type treeRows struct {
+	tree *_Tree
+}
+
+func (rows treeRows) Count() int {
+	return len(rows.tree.visible)
+}
+
+// DrawRow draws indentation guides, expansion glyph and title of node
+func (rows treeRows) DrawRow(p *_Painter, index int, selected bool) {
+	tree := rows.tree
+	node := tree.visible[index]
+	theme := p.Theme()
+	if tree.guides {
+		// Guide of ancestor continues while it has following siblings
+		ancestor := node
+		for level := node.depth - 1; level >= 0; level-- {
+			switch {
+			case ancestor == node && node.last():
+				p.Text(level*2, 0, "└─", theme.Border)
+			case ancestor == node:
+				p.Text(level*2, 0, "├─", theme.Border)
+			case !ancestor.last():
+				p.Put(level*2, 0, '│', theme.Border)
+			}
+			ancestor = ancestor.parent
+		}
+	}
+	x := node.depth * 2
+	switch {
+	case node.expandable() && node.expanded:
+		p.Put(x, 0, '▾', theme.SecondaryText)
+	case node.expandable():
+		p.Put(x, 0, '▸', theme.SecondaryText)
+	case tree.guides && node.depth > 0:
+		p.Put(x, 0, '─', theme.Border)
+	}
+	fg := theme.Text
+	if selected {
+		fg = theme.SelectionText
+	}
+	p.Text(x+2, 0, node.title, fg)
+}
+
+// _Tree – view showing hierarchy of nodes. Children of expanded nodes are
+// shown under them with indentation, visible nodes are rows of list, so tree
+// can show any number of them.
+type _Tree struct {
+	x, y, width, height int
+	awidth, aheight     int
+	roots               []*_TreeNode
+	visible             []*_TreeNode // nodes shown by list
+	list                *_List
+	glyphs              []*_AClickGesture // clicks on glyphs of visible rows
+	glyphNodes          []*_TreeNode
+	guides              bool
+	load                func(node *_TreeNode) []*_TreeNode
+	onSelectionChanged  func(node *_TreeNode)
+	onActivate          func(node *_TreeNode)
+	theme               *Theme
+	styleable
+	disableable
+	insetable
+	layoutable
+	windowHost
+}
+
+// getGesture implements View.
+func (*_Tree) getGesture() Gesture {
+	return nil
+}
+
+// hasGesture implements View.
+func (*_Tree) hasGesture() bool {
+	return false
+}
+
+func (tree *_Tree) getLogicalSize() (int, int) {
+	return tree.width, tree.height
+}
+
+func (tree *_Tree) getActualSize() (int, int) {
+	return tree.awidth, tree.aheight
+}
+
+func (tree *_Tree) setPos(x, y int) {
+	tree.x = x
+	tree.y = y
+}
+
+func (tree *_Tree) getPos() (int, int) {
+	return tree.x, tree.y
+}
+
+// setTheme implements themedView.
+func (tree *_Tree) setTheme(theme *Theme) {
+	tree.theme = theme
+}
+
+// indexOf returns index of visible node, -1 if node is not shown
+func (tree *_Tree) indexOf(node *_TreeNode) int {
+	for i, visible := range tree.visible {
+		if visible == node {
+			return i
+		}
+	}
+	return -1
+}
+
+// current returns node of the current row
+func (tree *_Tree) current() *_TreeNode {
+	if cursor := tree.list.cursor; cursor >= 0 && cursor < len(tree.visible) {
+		return tree.visible[cursor]
+	}
+	return nil
+}
+
+// shown returns node itself if it is visible or its closest visible ancestor
+func (tree *_Tree) shown(node *_TreeNode) int {
+	for ; node != nil; node = node.parent {
+		if index := tree.indexOf(node); index >= 0 {
+			return index
+		}
+	}
+	return -1
+}
+
+// rebuild collects visible nodes keeping the same nodes selected and current.
+// Rows of hidden nodes pass selection to their visible ancestors.
+func (tree *_Tree) rebuild() {
+	selected, current := tree.Selected(), tree.current()
+	tree.visible = tree.visible[:0]
+	var walk func(nodes []*_TreeNode, depth int)
+	walk = func(nodes []*_TreeNode, depth int) {
+		for _, node := range nodes {
+			node.depth = depth
+			tree.visible = append(tree.visible, node)
+			if node.expanded {
+				walk(node.children, depth+1)
+			}
+		}
+	}
+	walk(tree.roots, 0)
+	list := tree.list
+	list.selected = make(map[int]bool)
+	if index := tree.shown(selected); index >= 0 {
+		list.selected[index] = true
+	}
+	if index := tree.shown(current); index >= 0 {
+		list.cursor, list.anchor = index, index
+	}
+	list.Reload()
+	if index := tree.shown(selected); index >= 0 && index != tree.indexOf(selected) {
+		tree.changed()
+	}
+}
+
+// changed reports selected node
+func (tree *_Tree) changed() {
+	if tree.onSelectionChanged != nil {
+		tree.onSelectionChanged(tree.Selected())
+	}
+}
+
+// toggle expands collapsed node and collapses expanded one
+func (tree *_Tree) toggle(node *_TreeNode) {
+	if node.expanded {
+		tree.Collapse(node)
+	} else {
+		tree.Expand(node)
+	}
+}
+
+func (tree *_Tree) render(width, height int) [][]proto.Cell {
+	if width < 0 || height < 0 {
+		measuredWidth, measuredHeight := tree.measure(width, height)
+		width, height = measuredWidth.clamp(width), measuredHeight.clamp(height)
+	}
+	tree.awidth = width
+	tree.aheight = height
+	canvas := allocateCanvas(width, height)
+	placeView(canvas, tree.list, 0, 0, width, height)
+	return canvas
+}
+
+// measure implements measurer. Tree takes all space it is given and ideally
+// fits all visible nodes.
+func (tree *_Tree) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	ideal := 0
+	for _, node := range tree.visible {
+		ideal = max(ideal, node.depth*2+2+stringWidth(node.title))
+	}
+	width, height := flexibleSize(0, ideal), flexibleSize(0, len(tree.visible))
+	if tree.width >= 0 {
+		width = fixedSize(tree.width)
+	}
+	if tree.height >= 0 {
+		height = fixedSize(tree.height)
+	}
+	return width, height
+}
+
+// subviews returns list showing nodes
+func (tree *_Tree) subviews() []View {
+	return []View{tree.list}
+}
+
+// getChildrenGestures returns gestures of list and clicks on expansion
+// glyphs of visible rows over them
+func (tree *_Tree) getChildrenGestures(x, y int) []GestureDescriptor {
+	x, y = x+tree.x, y+tree.y
+	if !viewEnabled(tree.list) {
+		return nil
+	}
+	actors := tree.list.getChildrenGestures(x, y)
+	listX, listY := tree.list.getPos()
+	tree.glyphNodes = tree.glyphNodes[:0]
+	for index := tree.list.offset; index < len(tree.visible) && index-tree.list.offset < tree.list.aheight; index++ {
+		node := tree.visible[index]
+		if !node.expandable() || node.depth*2 >= tree.list.awidth {
+			continue
+		}
+		row := len(tree.glyphNodes)
+		if row == len(tree.glyphs) {
+			tree.glyphs = append(tree.glyphs, LClickGesture(1).OnEnded(func(inside bool) {
+				if inside && row < len(tree.glyphNodes) {
+					tree.toggle(tree.glyphNodes[row])
+				}
+			}))
+		}
+		tree.glyphNodes = append(tree.glyphNodes, node)
+		glyph := tree.glyphs[row]
+		glyph.x, glyph.y, glyph.width, glyph.height = listX+node.depth*2, listY+index-tree.list.offset, 1, 1
+		actors = append(actors, glyph.getGestureDescriptor(x, y))
+	}
+	return actors
+}
+
+// canFocus implements KeyHandler. Focus is given to rows of tree.
+func (tree *_Tree) canFocus() bool {
+	return false
+}
+
+// handleKey implements KeyHandler. Right expands the current node or moves
+// to its first child, Left collapses it or moves to its parent.
+func (tree *_Tree) handleKey(event *proto.EventRequest) bool {
+	node := tree.current()
+	if node == nil || event.Ch != 0 || event.Mod != 0 {
+		return false
+	}
+	switch event.Key {
+	case termbox.KeyArrowRight:
+		switch {
+		case !node.expanded:
+			tree.Expand(node)
+		case len(node.children) > 0:
+			tree.list.moveCursor(tree.list.cursor+1, false)
+		}
+	case termbox.KeyArrowLeft:
+		switch {
+		case node.expanded:
+			tree.Collapse(node)
+		case node.parent != nil:
+			tree.list.moveCursor(tree.indexOf(node.parent), false)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// focusChanged implements KeyHandler.
+func (tree *_Tree) focusChanged(focused bool) {}
+
+// Expand shows children of node loading them first for lazy node
+func (tree *_Tree) Expand(node *_TreeNode) *_Tree {
+	if node.lazy {
+		node.lazy = false
+		if tree.load != nil {
+			node.Add(tree.load(node)...)
+		}
+	}
+	node.expanded = len(node.children) > 0
+	tree.rebuild()
+	return tree
+}
+
+// Collapse hides children of node
+func (tree *_Tree) Collapse(node *_TreeNode) *_Tree {
+	node.expanded = false
+	tree.rebuild()
+	return tree
+}
+
+// Selected returns selected node, nil if no node is selected
+func (tree *_Tree) Selected() *_TreeNode {
+	if selected := tree.list.Selected(); len(selected) > 0 && selected[0] < len(tree.visible) {
+		return tree.visible[selected[0]]
+	}
+	return nil
+}
+
+// Select selects node expanding its ancestors and scrolls to it
+func (tree *_Tree) Select(node *_TreeNode) *_Tree {
+	for parent := node.parent; parent != nil; parent = parent.parent {
+		parent.expanded = true
+	}
+	tree.rebuild()
+	if index := tree.indexOf(node); index >= 0 {
+		tree.list.Select(index)
+		tree.list.ScrollTo(index)
+	}
+	return tree
+}
+
+// Reload shows nodes again, should be called after nodes are added or
+// removed
+func (tree *_Tree) Reload() *_Tree {
+	tree.rebuild()
+	return tree
+}
+
+// LoadChildren sets function returning children of lazy node, it is called
+// when node is expanded for the first time
+func (tree *_Tree) LoadChildren(load func(node *_TreeNode) []*_TreeNode) *_Tree {
+	tree.load = load
+	return tree
+}
+
+// Guides sets whether lines connecting nodes to their parents are drawn
+// (true by default)
+func (tree *_Tree) Guides(b bool) *_Tree {
+	tree.guides = b
+	return tree
+}
+
+// OnSelectionChanged sets action called with selected node when selection
+// changes
+func (tree *_Tree) OnSelectionChanged(action func(node *_TreeNode)) *_Tree {
+	tree.onSelectionChanged = action
+	return tree
+}
+
+// OnActivate sets action called when node is activated with Enter or double
+// click. Double click also expands or collapses node.
+func (tree *_Tree) OnActivate(action func(node *_TreeNode)) *_Tree {
+	tree.onActivate = action
+	return tree
+}
+
+func (tree *_Tree) SetSize(width, height int) *_Tree {
+	tree.width = width
+	tree.height = height
+	return tree
+}
+
+// Disabled disables (or enables) tree
+func (tree *_Tree) Disabled(b bool) *_Tree {
+	tree.disabled = b
+	return tree
+}
+
+// StyleClass sets stylesheet classes of tree
+func (tree *_Tree) StyleClass(classes ...string) *_Tree {
+	tree.classes = classes
+	return tree
+}
+
+func (tree *_Tree) Padding(sides ...int) *_Tree {
+	tree.padding = edgesOf(sides...)
+	return tree
+}
+
+func (tree *_Tree) Margin(sides ...int) *_Tree {
+	tree.margin = edgesOf(sides...)
+	return tree
+}
+
+func (tree *_Tree) Flex(weight int) *_Tree {
+	tree.setFlex(weight)
+	return tree
+}
+
+func (tree *_Tree) LayoutPriority(priority int) *_Tree {
+	tree.setPriority(priority)
+	return tree
+}
+
+func (tree *_Tree) MinWidth(width int) *_Tree {
+	tree.setWidthLimits(max(0, width), -1)
+	return tree
+}
+
+func (tree *_Tree) MaxWidth(width int) *_Tree {
+	tree.setWidthLimits(-1, max(0, width))
+	return tree
+}
+
+func (tree *_Tree) MinHeight(height int) *_Tree {
+	tree.setHeightLimits(max(0, height), -1)
+	return tree
+}
+
+func (tree *_Tree) MaxHeight(height int) *_Tree {
+	tree.setHeightLimits(-1, max(0, height))
+	return tree
+}
+
+// Tree creates tree showing root nodes, e.g. directory with subdirectories
+// read on expansion:
+//
+//	Tree(TreeNode("/").Lazy().SetData("/")).LoadChildren(func(node *_TreeNode) []*_TreeNode {
+//		entries, _ := os.ReadDir(node.Data().(string))
+//		...
+//	})
+func Tree(roots ...*_TreeNode) *_Tree {
+	tree := new(_Tree)
+	tree.roots = roots
+	tree.kind = "Tree"
+	tree.width = -1
+	tree.height = -1
+	tree.guides = true
+	tree.list = List(treeRows{tree}).OnSelectionChanged(func([]int) {
+		tree.changed()
+	}).OnDoubleClick(func(index int) {
+		if node := tree.visible[index]; node.expandable() {
+			tree.toggle(node)
+		}
+	}).OnActivate(func(index int) {
+		if tree.onActivate != nil && index < len(tree.visible) {
+			tree.onActivate(tree.visible[index])
+		}
+	})
+	tree.rebuild()
+	return tree
+}
@@ -0,0 +1,104 @@
+package fwsui
+
+import (
+	"testing"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestTreeCollapseMovesSelectionToAncestor(t *testing.T) {
+	leaf := TreeNode("main.go")
+	src := TreeNode("src", TreeNode("cmd", leaf))
+	root := TreeNode("project", src, TreeNode("README"))
+	var reported []*_TreeNode
+	tree := Tree(root).OnSelectionChanged(func(node *_TreeNode) {
+		reported = append(reported, node)
+	})
+	tree.Select(leaf)
+	if tree.Selected() != leaf || len(tree.visible) != 5 {
+		t.Fatalf("selected %v of %d visible nodes, want main.go of 5", tree.Selected(), len(tree.visible))
+	}
+	reported = nil
+	tree.Collapse(src)
+	if tree.Selected() != src {
+		t.Errorf("selected = %v, want src", tree.Selected())
+	}
+	if current := tree.current(); current != src {
+		t.Errorf("current = %v, want src", current)
+	}
+	if len(reported) != 1 || reported[0] != src {
+		t.Errorf("reported = %v, want [src]", reported)
+	}
+	reported = nil
+	tree.Collapse(root)
+	if tree.Selected() != root || len(tree.visible) != 1 {
+		t.Errorf("selected %v of %d visible nodes, want project of 1", tree.Selected(), len(tree.visible))
+	}
+	tree.Expand(root)
+	if tree.Selected() != root {
+		t.Errorf("selected = %v after expand, want project", tree.Selected())
+	}
+	if len(reported) != 1 {
+		t.Errorf("selection is reported %d times, want 1", len(reported))
+	}
+}
+
+func TestTreeRebuildKeepsSelectedNode(t *testing.T) {
+	docs := TreeNode("docs", TreeNode("a"), TreeNode("b"))
+	readme := TreeNode("README")
+	tree := Tree(docs, readme)
+	tree.Select(readme)
+	if index := tree.list.Selected(); len(index) != 1 || index[0] != 1 {
+		t.Fatalf("selected rows = %v, want [1]", index)
+	}
+	// Rows above selected node are inserted
+	tree.Expand(docs)
+	if tree.Selected() != readme || tree.list.cursor != 3 {
+		t.Errorf("selected %v at %d, want README at 3", tree.Selected(), tree.list.cursor)
+	}
+}
+
+func TestTreeLazyChildren(t *testing.T) {
+	loads := 0
+	dir := TreeNode("dir").Lazy()
+	tree := Tree(dir).LoadChildren(func(node *_TreeNode) []*_TreeNode {
+		loads++
+		return []*_TreeNode{TreeNode("file")}
+	})
+	if len(tree.visible) != 1 || !dir.expandable() {
+		t.Fatalf("lazy node: %d visible, expandable %v", len(tree.visible), dir.expandable())
+	}
+	tree.Expand(dir).Collapse(dir).Expand(dir)
+	if loads != 1 {
+		t.Errorf("children are loaded %d times, want 1", loads)
+	}
+	if len(tree.visible) != 2 || tree.visible[1].Parent() != dir {
+		t.Errorf("%d visible nodes, want dir and its file", len(tree.visible))
+	}
+	empty := TreeNode("empty").Lazy()
+	tree.roots = append(tree.roots, empty)
+	tree.LoadChildren(nil).Reload()
+	if tree.Expand(empty); empty.Expanded() {
+		t.Error("node without children is expanded")
+	}
+}
+
+func TestTreeKeys(t *testing.T) {
+	child := TreeNode("child")
+	parent := TreeNode("parent", child)
+	tree := Tree(parent)
+	tree.Select(child)
+	tree.handleKey(keyEvent(termbox.KeyArrowLeft, 0))
+	if tree.current() != parent || !parent.Expanded() {
+		t.Errorf("Left on leaf: current %v, expanded %v, want parent, true", tree.current(), parent.Expanded())
+	}
+	tree.handleKey(keyEvent(termbox.KeyArrowLeft, 0))
+	if parent.Expanded() {
+		t.Error("Left doesn't collapse node")
+	}
+	tree.handleKey(keyEvent(termbox.KeyArrowRight, 0))
+	tree.handleKey(keyEvent(termbox.KeyArrowRight, 0))
+	if tree.current() != child {
+		t.Errorf("Right: current %v, want child", tree.current())
+	}
+}
@@ -1,9 +1,8 @@
 package fwsui
 
 import (
-	"unicode/utf8"
-
 	proto "github.com/Nekhaevalex/fwsprotocol"
+	"github.com/Nekhaevalex/fwsui/color"
 
 	"github.com/nsf/termbox-go"
 )
@@ -22,11 +21,19 @@ type View interface {
 
 type _Text struct {
 	// Position and value
-	x      int
-	y      int
-	width  int
-	height int
-	text   string
+	x          int
+	y          int
+	width      int
+	height     int
+	text       string
+	attributed *_AttributedString // styled spans of text (if any)
+	// Layout
+	autoWidth     bool // width is width of content, SetSize wasn't called
+	autoHeight    bool // height is computed from content
+	wrap          WrapMode
+	truncation    Truncation
+	verticalAlign Align
+	lineLimit     int
 	// Attributes
 	align      Align
 	bold       bool
@@ -38,6 +45,18 @@ type _Text struct {
 	reverse    bool
 	foreground proto.Color
 	background proto.Color
+	fgRole     ColorRole // theme role of foreground (NoRole for explicit color)
+	bgRole     ColorRole // theme role of background (NoRole for explicit color)
+	theme      *Theme
+	// Interaction
+	interactive bool // controls get default look of states without style
+	state       InteractionState
+	stateStyles map[InteractionState]*_Style
+	onActivate  func() // action of control activated with keyboard
+	styleable
+	disableable
+	insetable
+	layoutable
 	// Gesture part
 	gestureFlag     bool
 	gesture         Gesture
@@ -55,6 +74,34 @@ func (text *_Text) Align(a Align) *_Text {
 	return text
 }
 
+// VerticalAlign sets alignment of lines inside text area: Left is top, Right
+// is bottom.
+func (text *_Text) VerticalAlign(a Align) *_Text {
+	text.verticalAlign = a
+	return text
+}
+
+// Wrap sets how lines wider than text are wrapped. Text of default size
+// gets narrower than its content when container has no space for it.
+func (text *_Text) Wrap(mode WrapMode) *_Text {
+	text.wrap = mode
+	return text
+}
+
+// Truncate sets where lines wider than text are cut with "…". Text of
+// default size gets narrower than its content when container has no space
+// for it.
+func (text *_Text) Truncate(mode Truncation) *_Text {
+	text.truncation = mode
+	return text
+}
+
+// LineLimit sets maximal amount of shown lines, 0 means no limit
+func (text *_Text) LineLimit(n int) *_Text {
+	text.lineLimit = max(0, n)
+	return text
+}
+
 func (text *_Text) Bold(b bool) *_Text {
 	text.bold = b
 	return text
@@ -92,14 +139,169 @@ func (text *_Text) Reverse(b bool) *_Text {
 
 func (text *_Text) Foreground(c proto.Color) *_Text {
 	text.foreground = c
+	text.fgRole = NoRole
 	return text
 }
 
 func (text *_Text) Background(c proto.Color) *_Text {
 	text.background = c
+	text.bgRole = NoRole
+	return text
+}
+
+// ForegroundRole makes foreground follow theme color of role
+func (text *_Text) ForegroundRole(role ColorRole) *_Text {
+	text.fgRole = role
+	text.resolveColors()
+	return text
+}
+
+// BackgroundRole makes background follow theme color of role
+func (text *_Text) BackgroundRole(role ColorRole) *_Text {
+	text.bgRole = role
+	text.resolveColors()
+	return text
+}
+
+// resolveColors takes colors with roles from theme
+func (text *_Text) resolveColors() {
+	theme := themeOrDefault(text.theme)
+	if text.fgRole != NoRole {
+		text.foreground = theme.Color(text.fgRole)
+	}
+	if text.bgRole != NoRole {
+		text.background = theme.Color(text.bgRole)
+	}
+}
+
+// setTheme implements themedView.
+func (text *_Text) setTheme(theme *Theme) {
+	text.theme = theme
+	text.resolveColors()
+}
+
+// currentStyle returns style of text in its interaction state and reports
+// whether state has own style
+func (text *_Text) currentStyle() (*_Style, bool) {
+	return stateStyle(text.style, text.stateStyles, text.state)
+}
+
+// colors returns colors text is drawn with
+func (text *_Text) colors() (proto.Color, proto.Color) {
+	style, styled := text.currentStyle()
+	fg, bg := style.colors(text.foreground, text.background, text.theme)
+	if styled {
+		return fg, bg
+	}
+	// Disabled views are greyed out, controls also get default look of
+	// other states
+	if text.state == DisabledState {
+		theme := themeOrDefault(text.theme)
+		if bg.A > 0 {
+			bg = theme.Disabled
+		}
+		return theme.DisabledText, bg
+	}
+	if !text.interactive {
+		return fg, bg
+	}
+	switch text.state {
+	case HoverState:
+		return fg, hoverColor(bg)
+	case PressedState:
+		return pressedColor(fg), pressedColor(bg)
+	}
+	return fg, bg
+}
+
+// StateStyle sets style text is drawn with in interaction state
+func (text *_Text) StateStyle(state InteractionState, style *_Style) *_Text {
+	if text.stateStyles == nil {
+		text.stateStyles = make(map[InteractionState]*_Style)
+	}
+	text.stateStyles[state] = style
+	return text
+}
+
+// setInteractionState implements statefulView.
+func (text *_Text) setInteractionState(state InteractionState) {
+	text.state = state
+}
+
+// Disabled disables (or enables) text: its gesture doesn't fire, control
+// can't get focus and it's drawn with disabled style
+func (text *_Text) Disabled(b bool) *_Text {
+	text.disabled = b
+	return text
+}
+
+// Padding sets space between text and edges of view. Padding is filled with
+// text background and belongs to view gesture area.
+func (text *_Text) Padding(sides ...int) *_Text {
+	text.padding = edgesOf(sides...)
+	return text
+}
+
+// Margin sets empty space around view
+func (text *_Text) Margin(sides ...int) *_Text {
+	text.margin = edgesOf(sides...)
+	return text
+}
+
+func (text *_Text) Flex(weight int) *_Text {
+	text.setFlex(weight)
+	return text
+}
+
+func (text *_Text) LayoutPriority(priority int) *_Text {
+	text.setPriority(priority)
+	return text
+}
+
+func (text *_Text) MinWidth(width int) *_Text {
+	text.setWidthLimits(max(0, width), -1)
 	return text
 }
 
+func (text *_Text) MaxWidth(width int) *_Text {
+	text.setWidthLimits(-1, max(0, width))
+	return text
+}
+
+func (text *_Text) MinHeight(height int) *_Text {
+	text.setHeightLimits(max(0, height), -1)
+	return text
+}
+
+func (text *_Text) MaxHeight(height int) *_Text {
+	text.setHeightLimits(-1, max(0, height))
+	return text
+}
+
+// backgroundColor implements backgroundView.
+func (text *_Text) backgroundColor() proto.Color {
+	_, bg := text.colors()
+	return bg
+}
+
+// canFocus implements KeyHandler. Only enabled controls with keyboard action
+// can get focus.
+func (text *_Text) canFocus() bool {
+	return text.onActivate != nil && !text.disabled
+}
+
+// handleKey implements KeyHandler. Enter and Space activate control.
+func (text *_Text) handleKey(event *proto.EventRequest) bool {
+	if text.onActivate != nil && event.Ch == 0 && (event.Key == termbox.KeyEnter || event.Key == termbox.KeySpace) {
+		text.onActivate()
+		return true
+	}
+	return false
+}
+
+// focusChanged implements KeyHandler.
+func (text *_Text) focusChanged(focused bool) {}
+
 func (text *_Text) constructAttribute() proto.Attr {
 	var attr proto.Attr = 0
 	if text.bold {
@@ -123,28 +325,129 @@ func (text *_Text) constructAttribute() proto.Attr {
 	if text.reverse {
 		attr = attr | proto.Attr(termbox.AttrReverse)
 	}
-	return attr
+	style, styled := text.currentStyle()
+	if text.interactive && !styled && text.state == FocusedState {
+		attr = attr | proto.Attr(termbox.AttrUnderline)
+	}
+	return style.attributes(attr)
+}
+
+// StyleClass sets stylesheet classes of text
+func (text *_Text) StyleClass(classes ...string) *_Text {
+	text.classes = classes
+	return text
+}
+
+// styleKind sets stylesheet kind text is styled as
+func (text *_Text) styleKind(kind string) *_Text {
+	text.kind = kind
+	return text
 }
 
+// SetSize sets text area size. Negative values make size floating, zero
+// height makes it computed from content.
 func (text *_Text) SetSize(w, h int) *_Text {
 	text.width = w
 	text.height = h
+	text.autoWidth = false
+	text.autoHeight = h == 0
 	return text
 }
 
 func (text *_Text) SetText(s string) *_Text {
 	text.text = s
-	text.width = utf8.RuneCountInString(s)
+	text.attributed = nil
+	text.width = text.contentWidth()
+	return text
+}
+
+func (text *_Text) SetAttributedText(str *_AttributedString) *_Text {
+	text.SetText(str.String())
+	text.attributed = str
 	return text
 }
 
+// styleAt returns colors and attributes of character at byte offset of text
+func (text *_Text) styleAt(offset int, attr proto.Attr) (proto.Color, proto.Color, proto.Attr) {
+	fg, bg := text.colors()
+	if text.attributed == nil {
+		return fg, bg, attr
+	}
+	if span := text.attributed.spanAt(offset); span != nil {
+		if span.fgSet {
+			fg = span.foreground
+		}
+		if span.bgSet {
+			bg = span.background
+		}
+		attr = attr | span.attr
+	}
+	return fg, bg, attr
+}
+
+// contentWidth returns width of the widest line of text
+func (text *_Text) contentWidth() int {
+	width := 0
+	for _, line := range layoutLines(text.text, -1, NoWrap) {
+		width = max(width, graphemesWidth(line))
+	}
+	return width
+}
+
+// heightForWidth returns amount of lines text takes when it is laid out in
+// given width
+func (text *_Text) heightForWidth(width int) int {
+	if !text.autoHeight {
+		return text.height
+	}
+	lines := len(layoutLines(text.text, width, text.wrap))
+	if text.lineLimit > 0 {
+		lines = min(lines, text.lineLimit)
+	}
+	return lines
+}
+
+// measure implements measurer. Floating text ideally takes width of its
+// content, its height is the amount of lines in the width it gets. Wrapped
+// or truncated text of default size can be made narrower than its content.
+func (text *_Text) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	width := fixedSize(text.width)
+	switch {
+	case text.width < 0:
+		width = flexibleSize(0, text.contentWidth())
+	case text.autoWidth && (text.wrap != NoWrap || text.truncation != TruncateNone):
+		width = shrinkableSize(text.width)
+	}
+	lines := len(layoutLines(text.text, width.clamp(proposedWidth), text.wrap))
+	if text.lineLimit > 0 {
+		lines = min(lines, text.lineLimit)
+	}
+	var height sizeRange
+	switch {
+	case text.autoHeight:
+		height = fixedSize(lines)
+	case text.height >= 0:
+		height = fixedSize(text.height)
+	default:
+		height = flexibleSize(0, lines)
+	}
+	return width, height
+}
+
 func (text *_Text) getLogicalSize() (int, int) {
+	if text.autoHeight {
+		return text.width, text.heightForWidth(text.width)
+	}
 	return text.width, text.height
 }
 
+// getActualSize returns size text was rendered with (logical size until it
+// is rendered)
 func (text *_Text) getActualSize() (int, int) {
-	if text.width > 0 && text.height > 0 {
-		return text.getLogicalSize()
+	if text.awidth == 0 && text.aheight == 0 {
+		if width, height := text.getLogicalSize(); width > 0 && height > 0 {
+			return width, height
+		}
 	}
 	return text.awidth, text.aheight
 }
@@ -161,30 +464,57 @@ func (text *_Text) getPos() (int, int) {
 func (text *_Text) render(width, height int) [][]proto.Cell {
 	text.awidth = width
 	text.aheight = height
+	attr := text.constructAttribute()
+	foreground, background := text.colors()
 	canvas := allocateCanvas(width, height)
 	for x := 0; x < width; x++ {
 		for y := 0; y < height; y++ {
 			canvas[x][y].Ch = rune(" "[0])
-			canvas[x][y].Fg = text.foreground
-			canvas[x][y].Bg = text.background
-			canvas[x][y].Attribute = text.constructAttribute()
+			canvas[x][y].Fg = foreground
+			canvas[x][y].Bg = background
+			canvas[x][y].Attribute = attr
 		}
 	}
+	if height <= 0 {
+		return canvas
+	}
+
+	maxLines := height
+	if text.lineLimit > 0 {
+		maxLines = min(maxLines, text.lineLimit)
+	}
+	lines := fitLines(layoutLines(text.text, width, text.wrap), width, maxLines, text.truncation)
+	style, _ := text.currentStyle()
+	align, verticalAlign := style.alignment(text.align, text.verticalAlign)
 
-	var start_x int
-	switch text.align {
+	var start_y int
+	switch verticalAlign {
 	case Left:
-		start_x = 0
+		start_y = 0
 	case Center:
-		start_x = width/2 - utf8.RuneCountInString(text.text)/2
+		start_y = height/2 - len(lines)/2
 	case Right:
-		start_x = width - utf8.RuneCountInString(text.text)
+		start_y = height - len(lines)
 	}
 
-	start_y := height / 2
-	for x := start_x; x < min(utf8.RuneCountInString(text.text)+start_x, width); x++ {
-		if x >= 0 {
-			canvas[x][start_y].Ch = []rune(text.text)[x-start_x]
+	for i, line := range lines {
+		lineWidth := graphemesWidth(line)
+		var start_x int
+		switch align {
+		case Left:
+			start_x = 0
+		case Center:
+			start_x = width/2 - lineWidth/2
+		case Right:
+			start_x = width - lineWidth
+		}
+		x := start_x
+		for _, g := range line {
+			if x >= width {
+				break
+			}
+			fg, bg, gattr := text.styleAt(g.offset, attr)
+			x += putGrapheme(canvas, x, start_y+i, g, fg, bg, gattr)
 		}
 	}
 
@@ -205,16 +535,26 @@ func Text(s string) *_Text {
 	text := new(_Text)
 	text.text = s
 	text.align = Left
+	text.verticalAlign = Center
+	text.wrap = NoWrap
+	text.truncation = TruncateNone
+	text.fgRole = TextRole
+	text.resolveColors()
+	text.kind = "Text"
 	text.x = 0
 	text.y = 0
-	text.width = utf8.RuneCountInString(s)
-	text.height = 1
+	text.width = text.contentWidth()
+	text.height = 0
+	text.autoWidth = true
+	text.autoHeight = true
 	text.gestureFlag = false
 	return text
 }
 
 type _Spacer struct {
 	x, y, width, height int
+	insetable
+	layoutable
 }
 
 func (spacer *_Spacer) getLogicalSize() (int, int) {
@@ -244,6 +584,41 @@ func (spacer *_Spacer) SetSize(w, h int) *_Spacer {
 	return spacer
 }
 
+func (spacer *_Spacer) Margin(sides ...int) *_Spacer {
+	spacer.margin = edgesOf(sides...)
+	return spacer
+}
+
+func (spacer *_Spacer) Flex(weight int) *_Spacer {
+	spacer.setFlex(weight)
+	return spacer
+}
+
+func (spacer *_Spacer) LayoutPriority(priority int) *_Spacer {
+	spacer.setPriority(priority)
+	return spacer
+}
+
+func (spacer *_Spacer) MinWidth(width int) *_Spacer {
+	spacer.setWidthLimits(max(0, width), -1)
+	return spacer
+}
+
+func (spacer *_Spacer) MaxWidth(width int) *_Spacer {
+	spacer.setWidthLimits(-1, max(0, width))
+	return spacer
+}
+
+func (spacer *_Spacer) MinHeight(height int) *_Spacer {
+	spacer.setHeightLimits(max(0, height), -1)
+	return spacer
+}
+
+func (spacer *_Spacer) MaxHeight(height int) *_Spacer {
+	spacer.setHeightLimits(-1, max(0, height))
+	return spacer
+}
+
 func (spacer *_Spacer) getGesture() Gesture {
 	return nil
 }
@@ -261,64 +636,42 @@ func Spacer() *_Spacer {
 
 type _Button struct {
 	_Text
-	action  func(outlet *_Button)
-	pressed bool
+	action func(outlet *_Button)
+}
+
+// pressedColor returns color of pressed control part
+func pressedColor(c proto.Color) proto.Color {
+	return color.Darken(c, 0.25)
+}
+
+// hoverColor returns color of control part under pointer
+func hoverColor(c proto.Color) proto.Color {
+	return color.Lighten(c, 0.1)
 }
 
 func Button(s string, action func(outlet *_Button)) *_Button {
 	button := new(_Button)
 	button.text = s
 	button.align = Center
+	button.verticalAlign = Center
 	button.x = 0
 	button.y = 0
-	button.width = utf8.RuneCountInString(s) + 2
+	button.width = stringWidth(s) + 2
 	button.height = 1
 	button.action = action
-	button.foreground = White
-	button.background = Grey
-
-	buttonPressed := func() {
-		if button.pressed {
-			return
-		}
-		button.foreground.R /= 2
-		button.foreground.G /= 2
-		button.foreground.B /= 2
-
-		button.background.R /= 2
-		button.background.G /= 2
-		button.background.B /= 2
-	}
-
-	buttonUnpressed := func() {
-		if !button.pressed {
-			return
-		}
-		button.foreground.R *= 2
-		button.foreground.G *= 2
-		button.foreground.B *= 2
-
-		button.background.R *= 2
-		button.background.G *= 2
-		button.background.B *= 2
+	button.fgRole = ControlTextRole
+	button.bgRole = ControlRole
+	button.resolveColors()
+	button.kind = "Button"
+	button.interactive = true
+	button.onActivate = func() {
+		action(button)
 	}
 
-	buttonClickGesture := LClickGesture(1).OnChanged(func(inside bool) {
-		if inside {
-			buttonPressed()
-			button.pressed = true
-		} else {
-			buttonUnpressed()
-			button.pressed = false
-		}
-	}).OnEnded(func(inside bool) {
+	// Pressed state is tracked by window
+	buttonClickGesture := LClickGesture(1).OnEnded(func(inside bool) {
 		if inside {
-			buttonUnpressed()
-			button.pressed = false
 			action(button)
-		} else {
-			buttonUnpressed()
-			button.pressed = false
 		}
 	})
 
@@ -329,124 +682,196 @@ func Button(s string, action func(outlet *_Button)) *_Button {
 
 type _TextField struct {
 	resultText  *string
-	input       chan *proto.EventRequest
 	prompt      string
 	active      bool
-	typeIndex   int
-	selectIndex int
+	typeIndex   int // caret position (in grapheme clusters)
+	selectIndex int // selection anchor (in grapheme clusters)
+	scroll      int // first visible grapheme cluster
 	onFinish    func()
 	label       _Text
+	gesture     *_DragGesture
+	host        *_Window
+	styleable
+	disableable
+	insetable
+	layoutable
 }
 
-func (textfield *_TextField) enableInput() {
-	AppInstance().setInput(&textfield.input)
+// selection returns ordered bounds of selected grapheme clusters
+func (textfield *_TextField) selection() (int, int) {
+	return min(textfield.typeIndex, textfield.selectIndex), max(textfield.typeIndex, textfield.selectIndex)
 }
 
 func (textfield *_TextField) insertString(s string) {
-	leftI := textfield.typeIndex
-	rightI := textfield.selectIndex
-	runeForm := []rune(*textfield.resultText)
-	newRuneForm := append(append(runeForm[:leftI], []rune(s)...), runeForm[rightI:]...)
-	*textfield.resultText = string(newRuneForm)
-	textfield.typeIndex += utf8.RuneCountInString(s)
+	leftI, rightI := textfield.selection()
+	clusters := splitGraphemes(*textfield.resultText)
+	head := joinGraphemes(clusters[:leftI]) + s
+	*textfield.resultText = head + joinGraphemes(clusters[rightI:])
+	// Inserted combining marks may merge with previous cluster, so caret is
+	// placed after the re-segmented head
+	textfield.typeIndex = graphemeCount(head)
 	textfield.selectIndex = textfield.typeIndex
 }
 
 func (textfield *_TextField) deletePartOfString() {
-	leftI := textfield.typeIndex
-	rightI := textfield.selectIndex
+	leftI, rightI := textfield.selection()
+	clusters := splitGraphemes(*textfield.resultText)
 	if leftI != rightI {
-		runeForm := []rune(*textfield.resultText)
-		newRuneForm := append(runeForm[:leftI], runeForm[rightI:]...)
-		*textfield.resultText = string(newRuneForm)
-		if textfield.typeIndex > 0 {
-			textfield.typeIndex -= 1
-		} else {
-			textfield.typeIndex = 0
-		}
+		*textfield.resultText = joinGraphemes(clusters[:leftI]) + joinGraphemes(clusters[rightI:])
+		textfield.typeIndex = leftI
 		textfield.selectIndex = textfield.typeIndex
 	} else {
 		if leftI == 0 {
 			return
 		}
-		runeForm := []rune(*textfield.resultText)
-		newRuneForm := append(runeForm[:leftI-1], runeForm[leftI:]...)
-		*textfield.resultText = string(newRuneForm)
-		textfield.typeIndex -= 1
+		*textfield.resultText = joinGraphemes(clusters[:leftI-1]) + joinGraphemes(clusters[leftI:])
+		textfield.typeIndex = leftI - 1
 		textfield.selectIndex = textfield.typeIndex
 	}
 }
 
-func (textfield *_TextField) handleEvent() {
-	for textfield.active {
-		event := <-textfield.input
-		if event.Ch == 0 {
-			switch event.Key {
-			case termbox.KeyEnter:
-				textfield.active = false
-				textfield.deactivate()
-				textfield.onFinish()
-			case termbox.KeyEsc:
-				textfield.active = false
-				textfield.deactivate()
-			case termbox.KeySpace:
-				textfield.insertString(" ")
-				textfield.label.SetText(*textfield.resultText).SetSize(-1, 1)
-			case termbox.KeyArrowLeft:
-				if event.Mod != termbox.ModAlt {
-					if textfield.selectIndex > 0 {
-						textfield.selectIndex -= 1
-					}
-				} else {
-					if textfield.typeIndex > 0 {
-						textfield.typeIndex -= 1
-					}
-				}
-			case termbox.KeyArrowRight:
-				if event.Mod != termbox.ModAlt {
-					if textfield.selectIndex < utf8.RuneCountInString(*textfield.resultText) {
-						textfield.selectIndex += 1
-					}
-				} else {
-					if textfield.typeIndex < utf8.RuneCountInString(*textfield.resultText) {
-						textfield.typeIndex += 1
-					}
-				}
-			case termbox.KeyBackspace, termbox.KeyBackspace2:
-				textfield.deletePartOfString()
-				textfield.updateLabelView()
+// handleKey implements KeyHandler. Keys that are not used by text field
+// (e.g. Tab) are left to window.
+func (textfield *_TextField) handleKey(event *proto.EventRequest) bool {
+	if !textfield.active {
+		return false
+	}
+	length := graphemeCount(*textfield.resultText)
+	if event.Ch != 0 {
+		textfield.insertString(string(event.Ch))
+		textfield.updateLabelView()
+		return true
+	}
+	switch event.Key {
+	case termbox.KeyEnter:
+		textfield.blur()
+		textfield.onFinish()
+	case termbox.KeyEsc:
+		textfield.blur()
+	case termbox.KeySpace:
+		textfield.insertString(" ")
+		textfield.updateLabelView()
+	case termbox.KeyArrowLeft:
+		if event.Mod != termbox.ModAlt {
+			left, right := textfield.selection()
+			if left == right && left > 0 {
+				left -= 1
 			}
+			textfield.typeIndex = left
+			textfield.selectIndex = left
 		} else {
-			textfield.insertString(string(event.Ch))
-			textfield.updateLabelView()
+			if textfield.typeIndex > 0 {
+				textfield.typeIndex -= 1
+			}
 		}
+		textfield.updateLabelView()
+	case termbox.KeyArrowRight:
+		if event.Mod != termbox.ModAlt {
+			left, right := textfield.selection()
+			if left == right && right < length {
+				right += 1
+			}
+			textfield.typeIndex = right
+			textfield.selectIndex = right
+		} else {
+			if textfield.typeIndex < length {
+				textfield.typeIndex += 1
+			}
+		}
+		textfield.updateLabelView()
+	case termbox.KeyBackspace, termbox.KeyBackspace2:
+		textfield.deletePartOfString()
+		textfield.updateLabelView()
+	default:
+		return false
+	}
+	return true
+}
+
+// canFocus implements KeyHandler.
+func (textfield *_TextField) canFocus() bool {
+	return !textfield.disabled
+}
+
+// Disabled disables (or enables) text field: it can't be edited and is drawn
+// with disabled style
+func (textfield *_TextField) Disabled(b bool) *_TextField {
+	textfield.disabled = b
+	if b && textfield.active {
+		textfield.blur()
+	}
+	return textfield
+}
+
+// focusChanged implements KeyHandler. Text field edits text while it has
+// focus.
+func (textfield *_TextField) focusChanged(focused bool) {
+	if focused && !textfield.active {
+		textfield.activate()
+	} else if !focused && textfield.active {
+		textfield.deactivate()
+	}
+}
+
+// focus makes text field receive keyboard input
+func (textfield *_TextField) focus() {
+	if textfield.host != nil {
+		textfield.host.setFocus(textfield)
+	} else {
+		textfield.activate()
+	}
+}
+
+// blur stops editing and gives focus away
+func (textfield *_TextField) blur() {
+	if textfield.host != nil && textfield.host.focused == View(textfield) {
+		textfield.host.setFocus(nil)
+	} else {
+		textfield.deactivate()
 	}
 }
 
+// setHost implements hostedView.
+func (textfield *_TextField) setHost(window *_Window) {
+	textfield.host = window
+}
+
 func (textfield *_TextField) activate() {
 	textfield.active = true
-	if utf8.RuneCountInString(*textfield.resultText) == 0 {
-		textfield.label.Foreground(Black).SetText("").SetSize(-1, 1)
+	if len(*textfield.resultText) == 0 {
+		textfield.label.ForegroundRole(InputTextRole).SetText("").SetSize(-1, 1)
 	}
 	textfield.typeIndex = 0
 	textfield.selectIndex = 0
-	go textfield.handleEvent()
+	textfield.scroll = 0
 }
 
+// updateLabelView scrolls text so that caret stays visible and puts visible
+// part of text into the label
 func (textfield *_TextField) updateLabelView() {
 	realWidth, _ := textfield.label.getActualSize()
-	if utf8.RuneCountInString(*textfield.resultText) > realWidth {
-		runeForm := []rune(*textfield.resultText)[realWidth:]
-		textfield.label.SetText(string(runeForm)).SetSize(-1, 1)
-	} else {
-		textfield.label.SetText(*textfield.resultText).SetSize(-1, 1)
+	clusters := splitGraphemes(*textfield.resultText)
+	textfield.typeIndex = min(textfield.typeIndex, len(clusters))
+	textfield.selectIndex = min(textfield.selectIndex, len(clusters))
+	if textfield.typeIndex < textfield.scroll {
+		textfield.scroll = textfield.typeIndex
+	}
+	// Caret takes one cell after the last character
+	for textfield.scroll < textfield.typeIndex &&
+		graphemesWidth(clusters[textfield.scroll:textfield.typeIndex])+1 > realWidth {
+		textfield.scroll += 1
 	}
+	textfield.scroll = min(textfield.scroll, len(clusters))
+	textfield.label.SetText(joinGraphemes(clusters[textfield.scroll:])).SetSize(-1, 1)
 }
 
 func (textfield *_TextField) deactivate() {
 	textfield.active = false
-	if utf8.RuneCountInString(*textfield.resultText) == 0 {
-		textfield.label.SetText(textfield.prompt).Foreground(Grey).SetSize(-1, 1)
+	textfield.scroll = 0
+	if len(*textfield.resultText) == 0 {
+		textfield.label.SetText(textfield.prompt).ForegroundRole(PlaceholderRole).SetSize(-1, 1)
+	} else {
+		textfield.updateLabelView()
 	}
 }
 
@@ -457,13 +882,14 @@ func (textfield *_TextField) OnFinish(action func()) *_TextField {
 
 func TextField(text *string, prompt string) *_TextField {
 	textfield := new(_TextField)
-	textfield.input = make(chan *proto.EventRequest)
-	textfield.label.Background(LightGrey)
-	textfield.label.Foreground(Grey)
+	textfield.label.BackgroundRole(InputRole)
+	textfield.label.ForegroundRole(PlaceholderRole)
 	textfield.label.SetText(prompt)
 	textfield.prompt = prompt
 	textfield.resultText = text
+	textfield.kind = "TextField"
 	textfield.label.align = Left
+	textfield.label.verticalAlign = Center
 	textfield.label.x = 0
 	textfield.label.y = 0
 	textfield.label.width = -1
@@ -472,24 +898,36 @@ func TextField(text *string, prompt string) *_TextField {
 	textfield.active = false
 	textfield.onFinish = func() {}
 
+	if len(*text) > 0 {
+		textfield.label.SetText(*text).ForegroundRole(InputTextRole).SetSize(-1, 1)
+	}
+
+	// Converts mouse column to index of grapheme cluster under it
+	columnToIndex := func(x int) int {
+		clusters := splitGraphemes(*textfield.resultText)
+		col := max(0, x-textfield.gesture.descriptor.x)
+		return min(textfield.scroll+graphemeAtColumn(clusters[textfield.scroll:], col), len(clusters))
+	}
+
 	selectGesture := DragGesture().OnChanged(func(value Value) {
 		if !textfield.active {
-			textfield.active = true
-			textfield.activate()
-			textfield.enableInput()
-			go textfield.handleEvent()
+			textfield.focus()
 		}
-		sel1 := min(max(0, value.startLocationX-textfield.label.x), utf8.RuneCountInString(*textfield.resultText))
-		sel2 := min(max(0, value.locationX-textfield.label.x), utf8.RuneCountInString(*textfield.resultText))
-		textfield.typeIndex = min(sel1, sel2)
-		textfield.selectIndex = max(sel1, sel2)
+		textfield.selectIndex = columnToIndex(value.startLocationX)
+		textfield.typeIndex = columnToIndex(value.locationX)
 	}).OnEnded(func(value Value) {
 
 	})
+	textfield.gesture = selectGesture
 	textfield.label.Gesture(selectGesture)
 	return textfield
 }
 
+// measure implements measurer.
+func (textfield *_TextField) measure(proposedWidth, proposedHeight int) (sizeRange, sizeRange) {
+	return textfield.label.measure(proposedWidth, proposedHeight)
+}
+
 func (textfield *_TextField) getLogicalSize() (int, int) {
 	return textfield.label.getLogicalSize()
 }
@@ -504,18 +942,116 @@ func (textfield *_TextField) setPos(x, y int) {
 }
 func (textfield *_TextField) render(width, height int) [][]proto.Cell {
 	renderedView := textfield.label.render(width, height)
-	if textfield.active {
-		if textfield.typeIndex != textfield.selectIndex {
-			for i := textfield.typeIndex; i < textfield.selectIndex; i++ {
-				renderedView[i][0].Bg = Blue
-				renderedView[i][0].Fg = White
+	if !textfield.active || height <= 0 {
+		return renderedView
+	}
+	row := height / 2
+	clusters := splitGraphemes(*textfield.resultText)
+	scroll := min(textfield.scroll, len(clusters))
+	left, right := textfield.selection()
+	theme := themeOrDefault(textfield.label.theme)
+	// Cell columns of visible grapheme clusters
+	col := 0
+	for i := scroll; i < len(clusters) && col < width; i++ {
+		if i >= left && i < right {
+			for c := col; c < min(col+clusters[i].width, width); c++ {
+				renderedView[c][row].Bg = theme.Selection
+				renderedView[c][row].Fg = theme.SelectionText
 			}
-		} else {
-			renderedView[textfield.typeIndex][0].Ch = []rune("|")[0]
+		}
+		col += clusters[i].width
+	}
+	if left == right && textfield.typeIndex >= scroll {
+		caret := graphemesWidth(clusters[scroll:min(textfield.typeIndex, len(clusters))])
+		if caret < width {
+			// Caret replaces a wide character as a whole
+			if cellWidth(renderedView[caret][row].Ch) == 2 && caret+1 < width {
+				renderedView[caret+1][row].Ch = rune(" "[0])
+			}
+			renderedView[caret][row].Ch = []rune("|")[0]
 		}
 	}
 	return renderedView
 }
+
+func (textfield *_TextField) Padding(sides ...int) *_TextField {
+	textfield.padding = edgesOf(sides...)
+	return textfield
+}
+
+func (textfield *_TextField) Margin(sides ...int) *_TextField {
+	textfield.margin = edgesOf(sides...)
+	return textfield
+}
+
+func (textfield *_TextField) Flex(weight int) *_TextField {
+	textfield.setFlex(weight)
+	return textfield
+}
+
+func (textfield *_TextField) LayoutPriority(priority int) *_TextField {
+	textfield.setPriority(priority)
+	return textfield
+}
+
+func (textfield *_TextField) MinWidth(width int) *_TextField {
+	textfield.setWidthLimits(max(0, width), -1)
+	return textfield
+}
+
+func (textfield *_TextField) MaxWidth(width int) *_TextField {
+	textfield.setWidthLimits(-1, max(0, width))
+	return textfield
+}
+
+func (textfield *_TextField) MinHeight(height int) *_TextField {
+	textfield.setHeightLimits(max(0, height), -1)
+	return textfield
+}
+
+func (textfield *_TextField) MaxHeight(height int) *_TextField {
+	textfield.setHeightLimits(-1, max(0, height))
+	return textfield
+}
+
+// backgroundColor implements backgroundView.
+func (textfield *_TextField) backgroundColor() proto.Color {
+	return textfield.label.backgroundColor()
+}
+
+// setTheme implements themedView.
+func (textfield *_TextField) setTheme(theme *Theme) {
+	textfield.label.setTheme(theme)
+}
+
+// applyStyle implements styledView. Placeholder keeps its theme color.
+func (textfield *_TextField) applyStyle(style *_Style) {
+	textfield.style = style
+	if style != nil && style.foreground != nil && textfield.label.fgRole == PlaceholderRole {
+		labelStyle := *style
+		labelStyle.foreground = nil
+		style = &labelStyle
+	}
+	textfield.label.applyStyle(style)
+}
+
+// setInteractionState implements statefulView.
+func (textfield *_TextField) setInteractionState(state InteractionState) {
+	textfield.label.setInteractionState(state)
+}
+
+// StateStyle sets style text field is drawn with in interaction state
+func (textfield *_TextField) StateStyle(state InteractionState, style *_Style) *_TextField {
+	textfield.label.StateStyle(state, style)
+	return textfield
+}
+
+// StyleClass sets stylesheet classes of text field
+func (textfield *_TextField) StyleClass(classes ...string) *_TextField {
+	textfield.classes = classes
+	return textfield
+}
+
 func (textfield *_TextField) hasGesture() bool {
 	return textfield.label.hasGesture()
 }